@@ -0,0 +1,28 @@
+package main
+
+import (
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/topi314/campfire-tools/server/campfire/fake"
+)
+
+func main() {
+	addr := flag.String("addr", ":8086", "address to listen on")
+	fixturesPath := flag.String("fixtures", "cmd/campfire-fake/fixtures.example.json", "path to fixtures file")
+	flag.Parse()
+
+	fixtures, err := fake.LoadFixtures(*fixturesPath)
+	if err != nil {
+		slog.Error("Error while loading fixtures", slog.Any("err", err))
+		os.Exit(1)
+	}
+
+	slog.Info("Starting fake Campfire server...", slog.String("addr", *addr), slog.Int("clubs", len(fixtures.Clubs)), slog.Int("events", len(fixtures.Events)))
+	if err = http.ListenAndServe(*addr, fake.New(fixtures)); err != nil {
+		slog.Error("Error while running fake Campfire server", slog.Any("err", err))
+		os.Exit(1)
+	}
+}
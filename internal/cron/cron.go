@@ -0,0 +1,214 @@
+// Package cron parses cron schedules and calculates when they are due next.
+//
+// A schedule has the five standard fields: minute, hour, day of month, month and day of week.
+// Fields accept *, values, ranges (a-b), lists (a,b) and steps (*/n, a-b/n). Months and weekdays can also be named (JAN, MON).
+// L in the day of month field matches the last day of the month. The macros @hourly, @daily, @weekly, @monthly and @yearly are supported.
+// Like in cron, a day matches if either the day of month or the day of week matches, unless one of them is *.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSearchYears limits how far Next looks ahead, schedules like "0 0 30 2 *" never match.
+const maxSearchYears = 5
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var (
+	monthNames   = []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+	weekdayNames = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+)
+
+type field struct {
+	name  string
+	min   int
+	max   int
+	names []string
+}
+
+var (
+	minuteField     = field{name: "minute", min: 0, max: 59}
+	hourField       = field{name: "hour", min: 0, max: 23}
+	dayOfMonthField = field{name: "day of month", min: 1, max: 31}
+	monthField      = field{name: "month", min: 1, max: 12, names: monthNames}
+	dayOfWeekField  = field{name: "day of week", min: 0, max: 7, names: weekdayNames}
+)
+
+// Schedule is a parsed cron schedule. Times are matched in the location of the time passed to Next.
+type Schedule struct {
+	minutes     uint64
+	hours       uint64
+	daysOfMonth uint64
+	months      uint64
+	daysOfWeek  uint64
+
+	lastDayOfMonth bool
+	anyDayOfMonth  bool
+	anyDayOfWeek   bool
+}
+
+// Parse parses a cron schedule with five fields or a macro.
+func Parse(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if macro, ok := macros[strings.ToLower(spec)]; ok {
+		spec = macro
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return Schedule{}, fmt.Errorf("expected 5 fields, got %d", len(fields))
+	}
+
+	var (
+		s   Schedule
+		err error
+	)
+	if s.minutes, err = minuteField.parse(fields[0]); err != nil {
+		return Schedule{}, err
+	}
+	if s.hours, err = hourField.parse(fields[1]); err != nil {
+		return Schedule{}, err
+	}
+
+	dayOfMonth := fields[2]
+	if strings.EqualFold(dayOfMonth, "L") {
+		s.lastDayOfMonth = true
+	} else if s.daysOfMonth, err = dayOfMonthField.parse(dayOfMonth); err != nil {
+		return Schedule{}, err
+	}
+	s.anyDayOfMonth = dayOfMonth == "*" || dayOfMonth == "?"
+
+	if s.months, err = monthField.parse(fields[3]); err != nil {
+		return Schedule{}, err
+	}
+
+	if s.daysOfWeek, err = dayOfWeekField.parse(fields[4]); err != nil {
+		return Schedule{}, err
+	}
+	// 7 is Sunday as well
+	if s.daysOfWeek&(1<<7) != 0 {
+		s.daysOfWeek |= 1
+	}
+	s.anyDayOfWeek = fields[4] == "*" || fields[4] == "?"
+
+	return s, nil
+}
+
+// Next returns the first time after t the schedule matches, or the zero time if it does not match within the next years.
+func (s Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	end := t.AddDate(maxSearchYears, 0, 0)
+
+	for t.Before(end) {
+		if s.months&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hours&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minutes&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (s Schedule) matchesDay(t time.Time) bool {
+	dayOfMonth := s.daysOfMonth&(1<<uint(t.Day())) != 0
+	if s.lastDayOfMonth {
+		dayOfMonth = t.AddDate(0, 0, 1).Day() == 1
+	}
+	dayOfWeek := s.daysOfWeek&(1<<uint(t.Weekday())) != 0
+
+	if s.anyDayOfMonth || s.anyDayOfWeek {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
+
+// parse returns the values of the field as a bit set.
+func (f field) parse(value string) (uint64, error) {
+	var bits uint64
+	for part := range strings.SplitSeq(value, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid %s step: %s", f.name, stepPart)
+			}
+		}
+
+		var from, to int
+		switch {
+		case rangePart == "*" || rangePart == "?":
+			from, to = f.min, f.max
+		case strings.Contains(rangePart, "-"):
+			fromPart, toPart, _ := strings.Cut(rangePart, "-")
+			var err error
+			if from, err = f.parseValue(fromPart); err != nil {
+				return 0, err
+			}
+			if to, err = f.parseValue(toPart); err != nil {
+				return 0, err
+			}
+			if from > to {
+				return 0, fmt.Errorf("invalid %s range: %s", f.name, rangePart)
+			}
+		default:
+			var err error
+			if from, err = f.parseValue(rangePart); err != nil {
+				return 0, err
+			}
+			to = from
+			// a step on a single value runs until the end of the field like in cron
+			if hasStep {
+				to = f.max
+			}
+		}
+
+		for i := from; i <= to; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+	return bits, nil
+}
+
+func (f field) parseValue(value string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(value, name) {
+			// month names start at 1, weekday names at 0
+			return i + f.min, nil
+		}
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil || i < f.min || i > f.max {
+		return 0, fmt.Errorf("invalid %s: %s", f.name, value)
+	}
+	return i, nil
+}
@@ -0,0 +1,105 @@
+// Package ical writes RFC 5545 iCalendar feeds.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	dateTimeFormat = "20060102T150405Z"
+	maxLineLength  = 75
+)
+
+type Calendar struct {
+	ProdID string
+	Name   string
+	Events []Event
+}
+
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Start       time.Time
+	End         time.Time
+}
+
+// Encode writes the calendar to w. Times are written in UTC and lines are folded after 75 octets.
+func (c Calendar) Encode(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	now := time.Now()
+
+	writeLine(bw, "BEGIN", "VCALENDAR")
+	writeLine(bw, "VERSION", "2.0")
+	writeLine(bw, "PRODID", c.ProdID)
+	writeLine(bw, "CALSCALE", "GREGORIAN")
+	writeLine(bw, "METHOD", "PUBLISH")
+	if c.Name != "" {
+		writeLine(bw, "X-WR-CALNAME", escape(c.Name))
+	}
+
+	for _, event := range c.Events {
+		writeLine(bw, "BEGIN", "VEVENT")
+		writeLine(bw, "UID", event.UID)
+		writeLine(bw, "DTSTAMP", formatTime(now))
+		writeLine(bw, "DTSTART", formatTime(event.Start))
+		if !event.End.IsZero() {
+			writeLine(bw, "DTEND", formatTime(event.End))
+		}
+		writeLine(bw, "SUMMARY", escape(event.Summary))
+		if event.Description != "" {
+			writeLine(bw, "DESCRIPTION", escape(event.Description))
+		}
+		if event.Location != "" {
+			writeLine(bw, "LOCATION", escape(event.Location))
+		}
+		if event.URL != "" {
+			writeLine(bw, "URL", event.URL)
+		}
+		writeLine(bw, "END", "VEVENT")
+	}
+
+	writeLine(bw, "END", "VCALENDAR")
+	return bw.Flush()
+}
+
+// escape escapes a TEXT property value.
+func escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", "",
+	).Replace(s)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(dateTimeFormat)
+}
+
+// writeLine writes a content line, folding it into lines of at most 75 octets without splitting UTF-8 characters.
+func writeLine(w *bufio.Writer, name string, value string) {
+	line := name + ":" + value
+	limit := maxLineLength
+	for len(line) > limit {
+		i := limit
+		for i > 0 && !utf8.RuneStart(line[i]) {
+			i--
+		}
+		_, _ = w.WriteString(line[:i])
+		_, _ = w.WriteString("\r\n ")
+		line = line[i:]
+		// continuation lines start with a space, which counts towards the limit
+		limit = maxLineLength - 1
+	}
+	_, _ = w.WriteString(line)
+	_, _ = w.WriteString("\r\n")
+}
@@ -0,0 +1,164 @@
+// Package openapi describes HTTP APIs as OpenAPI 3.1 documents and generates their schemas from Go types.
+package openapi
+
+import (
+	"slices"
+	"strings"
+)
+
+const Version = "3.1.0"
+
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Servers    []Server            `json:"servers,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+// PathItem maps lower case HTTP methods to their operation.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+// Scopes returns all scopes required by the security requirements of the operation.
+func (o *Operation) Scopes() []string {
+	var scopes []string
+	for _, requirement := range o.Security {
+		for _, s := range requirement {
+			scopes = append(scopes, s...)
+		}
+	}
+	return scopes
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas,omitempty"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	Scheme      string `json:"scheme,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 any                `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+}
+
+// RefName returns the name of the referenced component schema or an empty string if the schema is not a reference.
+func (s *Schema) RefName() string {
+	return strings.TrimPrefix(s.Ref, refPrefix)
+}
+
+// IsRequired reports whether the property is required.
+func (s *Schema) IsRequired(property string) bool {
+	return slices.Contains(s.Required, property)
+}
+
+// Nullable reports whether the schema allows null.
+func (s *Schema) Nullable() bool {
+	types, ok := s.Type.([]string)
+	return ok && slices.Contains(types, "null")
+}
+
+// TypeName returns a short human-readable description of the schema type, e.g. "array of ExportMember".
+func (s *Schema) TypeName() string {
+	if s == nil {
+		return "any"
+	}
+	switch {
+	case s.Ref != "":
+		return s.RefName()
+	case len(s.OneOf) > 0 || len(s.AnyOf) > 0:
+		var names []string
+		for _, schema := range append(s.OneOf, s.AnyOf...) {
+			names = append(names, schema.TypeName())
+		}
+		return strings.Join(names, " or ")
+	}
+
+	var (
+		types    []string
+		nullable bool
+	)
+	switch t := s.Type.(type) {
+	case string:
+		types = []string{t}
+	case []string:
+		for _, typ := range t {
+			if typ == "null" {
+				nullable = true
+				continue
+			}
+			types = append(types, typ)
+		}
+	default:
+		return "any"
+	}
+
+	name := strings.Join(types, " or ")
+	switch {
+	case s.Items != nil:
+		name += " of " + s.Items.TypeName()
+	case s.AdditionalProperties != nil:
+		name += " of " + s.AdditionalProperties.TypeName()
+	case s.Format != "":
+		name += " (" + s.Format + ")"
+	}
+	if nullable {
+		name += " or null"
+	}
+	return name
+}
@@ -0,0 +1,217 @@
+// Package s3 uploads objects to S3 compatible storage like AWS S3 or MinIO, signed with AWS Signature Version 4.
+package s3
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	amzDateFormat    = "20060102T150405Z"
+	amzShortFormat   = "20060102"
+	signingAlgorithm = "AWS4-HMAC-SHA256"
+)
+
+type Config struct {
+	Enabled         bool   `toml:"enabled"`
+	Endpoint        string `toml:"endpoint"`
+	Region          string `toml:"region"`
+	Bucket          string `toml:"bucket"`
+	AccessKeyID     string `toml:"access_key_id"`
+	SecretAccessKey string `toml:"secret_access_key"`
+	PathStyle       bool   `toml:"path_style"`
+}
+
+func (c Config) String() string {
+	return fmt.Sprintf("\n Enabled: %t\n Endpoint: %s\n Region: %s\n Bucket: %s\n AccessKeyID: %s\n SecretAccessKey: %s\n PathStyle: %t",
+		c.Enabled,
+		c.Endpoint,
+		c.Region,
+		c.Bucket,
+		c.AccessKeyID,
+		strings.Repeat("*", len(c.SecretAccessKey)),
+		c.PathStyle,
+	)
+}
+
+type Client struct {
+	cfg        Config
+	endpoint   *url.URL
+	httpClient *http.Client
+}
+
+func New(cfg Config, httpClient *http.Client) (*Client, error) {
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil || (endpoint.Scheme != "https" && endpoint.Scheme != "http") || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid endpoint: %s", cfg.Endpoint)
+	}
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("no bucket configured")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+
+	return &Client{
+		cfg:        cfg,
+		endpoint:   endpoint,
+		httpClient: httpClient,
+	}, nil
+}
+
+// Bucket returns the bucket objects are uploaded to.
+func (c *Client) Bucket() string {
+	return c.cfg.Bucket
+}
+
+// PutObject uploads the body to the key. The body is read twice, once to hash it and once to send it.
+func (c *Client) PutObject(ctx context.Context, key string, body io.ReadSeeker, contentType string) error {
+	h := sha256.New()
+	size, err := io.Copy(h, body)
+	if err != nil {
+		return fmt.Errorf("failed to hash object: %w", err)
+	}
+	if _, err = body.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind object: %w", err)
+	}
+
+	rq, err := http.NewRequestWithContext(ctx, http.MethodPut, c.objectURL(key), io.NopCloser(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	rq.ContentLength = size
+	rq.Header.Set("Content-Type", contentType)
+	c.sign(rq, hex.EncodeToString(h.Sum(nil)), time.Now())
+
+	rs, err := c.httpClient.Do(rq)
+	if err != nil {
+		return fmt.Errorf("failed to upload object: %w", err)
+	}
+	defer rs.Body.Close()
+
+	if rs.StatusCode < 200 || rs.StatusCode >= 300 {
+		rsBody, _ := io.ReadAll(io.LimitReader(rs.Body, 512))
+		return fmt.Errorf("storage responded with status %d: %s", rs.StatusCode, rsBody)
+	}
+
+	return nil
+}
+
+// objectURL returns the URL of the key, with the bucket in the path or as a subdomain of the endpoint.
+func (c *Client) objectURL(key string) string {
+	host := c.endpoint.Host
+	objectPath := strings.TrimSuffix(c.endpoint.EscapedPath(), "/")
+	if c.cfg.PathStyle {
+		objectPath += "/" + escape(c.cfg.Bucket)
+	} else {
+		host = c.cfg.Bucket + "." + host
+	}
+	return c.endpoint.Scheme + "://" + host + objectPath + "/" + escapePath(strings.TrimPrefix(key, "/"))
+}
+
+// sign adds the Authorization header to the request. All headers of the request and the host are signed.
+func (c *Client) sign(rq *http.Request, payloadHash string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format(amzDateFormat)
+	scope := now.Format(amzShortFormat) + "/" + c.cfg.Region + "/s3/aws4_request"
+
+	rq.Header.Set("X-Amz-Date", amzDate)
+	rq.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{
+		"host": rq.URL.Host,
+	}
+	for name, values := range rq.Header {
+		headers[strings.ToLower(name)] = strings.Join(values, ",")
+	}
+	names := slices.Sorted(maps.Keys(headers))
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		rq.Method,
+		rq.URL.EscapedPath(),
+		canonicalQuery(rq.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	stringToSign := strings.Join([]string{
+		signingAlgorithm,
+		amzDate,
+		scope,
+		hashHex(canonicalRequest),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+c.cfg.SecretAccessKey), now.Format(amzShortFormat))
+	key = hmacSHA256(key, c.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	rq.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		signingAlgorithm,
+		c.cfg.AccessKeyID,
+		scope,
+		signedHeaders,
+		signature,
+	))
+}
+
+func canonicalQuery(query url.Values) string {
+	var parts []string
+	for _, key := range slices.Sorted(maps.Keys(query)) {
+		for _, value := range slices.Sorted(slices.Values(query[key])) {
+			parts = append(parts, escape(key)+"="+escape(value))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// escapePath escapes every segment of the path like escape, keeping the slashes.
+func escapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		segments[i] = escape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// escape percent encodes everything except the unreserved characters of RFC 3986, as required by Signature Version 4.
+func escape(s string) string {
+	var sb strings.Builder
+	for _, b := range []byte(s) {
+		if (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || (b >= '0' && b <= '9') || b == '-' || b == '_' || b == '.' || b == '~' {
+			sb.WriteByte(b)
+			continue
+		}
+		fmt.Fprintf(&sb, "%%%02X", b)
+	}
+	return sb.String()
+}
+
+func hashHex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
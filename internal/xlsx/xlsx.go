@@ -0,0 +1,254 @@
+// Package xlsx writes minimal Office Open XML (.xlsx) workbooks.
+// Sheets are written one row at a time, so workbooks of any size can be streamed.
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	ContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+	maxSheetNameLength = 31
+	xmlHeader          = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+)
+
+var ErrClosed = errors.New("xlsx: writer is closed")
+
+// Writer writes a workbook to the underlying writer. Sheets appear in the order they are created.
+type Writer struct {
+	zw     *zip.Writer
+	sheets []string
+	sheet  *Sheet
+	closed bool
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{
+		zw: zip.NewWriter(w),
+	}
+}
+
+// Sheet is a worksheet being written. It is only valid until the next call to Writer.NewSheet or Writer.Close.
+type Sheet struct {
+	w    io.Writer
+	rows int
+}
+
+// NewSheet finishes the current sheet and starts a new one.
+// The name is cleaned up to be a valid sheet name and made unique within the workbook.
+func (w *Writer) NewSheet(name string) (*Sheet, error) {
+	if w.closed {
+		return nil, ErrClosed
+	}
+	if err := w.finishSheet(); err != nil {
+		return nil, err
+	}
+
+	w.sheets = append(w.sheets, w.sheetName(name))
+	f, err := w.zw.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", len(w.sheets)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sheet: %w", err)
+	}
+
+	if _, err = io.WriteString(f, xmlHeader+`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`); err != nil {
+		return nil, fmt.Errorf("failed to write sheet: %w", err)
+	}
+
+	w.sheet = &Sheet{w: f}
+	return w.sheet, nil
+}
+
+// WriteRow appends a row to the sheet. Strings are written as text, integers and floats as numbers and bools as booleans.
+// Any other value is formatted with fmt.Sprint.
+func (s *Sheet) WriteRow(values ...any) error {
+	s.rows++
+
+	var sb strings.Builder
+	sb.WriteString(`<row r="`)
+	sb.WriteString(strconv.Itoa(s.rows))
+	sb.WriteString(`">`)
+	for _, value := range values {
+		switch v := value.(type) {
+		case int:
+			sb.WriteString(`<c><v>` + strconv.Itoa(v) + `</v></c>`)
+		case int64:
+			sb.WriteString(`<c><v>` + strconv.FormatInt(v, 10) + `</v></c>`)
+		case float64:
+			sb.WriteString(`<c><v>` + strconv.FormatFloat(v, 'f', -1, 64) + `</v></c>`)
+		case bool:
+			b := "0"
+			if v {
+				b = "1"
+			}
+			sb.WriteString(`<c t="b"><v>` + b + `</v></c>`)
+		case string:
+			writeString(&sb, v)
+		default:
+			writeString(&sb, fmt.Sprint(v))
+		}
+	}
+	sb.WriteString(`</row>`)
+
+	if _, err := io.WriteString(s.w, sb.String()); err != nil {
+		return fmt.Errorf("failed to write row: %w", err)
+	}
+	return nil
+}
+
+// WriteRows appends a row to the sheet for every record.
+func (s *Sheet) WriteRows(records [][]string) error {
+	for _, record := range records {
+		values := make([]any, len(record))
+		for i, value := range record {
+			values[i] = value
+		}
+		if err := s.WriteRow(values...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeString(sb *strings.Builder, s string) {
+	sb.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+	_ = xml.EscapeText(sb, []byte(s))
+	sb.WriteString(`</t></is></c>`)
+}
+
+// Close finishes the current sheet and writes the workbook. It does not close the underlying writer.
+// A workbook needs at least one sheet, an empty one is added if none was created.
+func (w *Writer) Close() error {
+	if w.closed {
+		return ErrClosed
+	}
+	if len(w.sheets) == 0 {
+		if _, err := w.NewSheet("Sheet1"); err != nil {
+			return err
+		}
+	}
+	if err := w.finishSheet(); err != nil {
+		return err
+	}
+	w.closed = true
+
+	var (
+		sheets        strings.Builder
+		relationships strings.Builder
+		overrides     strings.Builder
+	)
+	for i, name := range w.sheets {
+		id := i + 1
+		sheets.WriteString(fmt.Sprintf(`<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escapeAttr(name), id, id))
+		relationships.WriteString(fmt.Sprintf(`<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, id, id))
+		overrides.WriteString(fmt.Sprintf(`<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, id))
+	}
+
+	files := []struct {
+		name    string
+		content string
+	}{
+		{
+			name: "[Content_Types].xml",
+			content: `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+				`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+				`<Default Extension="xml" ContentType="application/xml"/>` +
+				`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+				overrides.String() +
+				`</Types>`,
+		},
+		{
+			name: "_rels/.rels",
+			content: `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+				`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+				`</Relationships>`,
+		},
+		{
+			name: "xl/workbook.xml",
+			content: `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+				`<sheets>` + sheets.String() + `</sheets>` +
+				`</workbook>`,
+		},
+		{
+			name: "xl/_rels/workbook.xml.rels",
+			content: `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+				relationships.String() +
+				`</Relationships>`,
+		},
+	}
+	for _, file := range files {
+		f, err := w.zw.Create(file.name)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", file.name, err)
+		}
+		if _, err = io.WriteString(f, xmlHeader+file.content); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+	}
+
+	return w.zw.Close()
+}
+
+func (w *Writer) finishSheet() error {
+	if w.sheet == nil {
+		return nil
+	}
+	sheet := w.sheet
+	w.sheet = nil
+	if _, err := io.WriteString(sheet.w, `</sheetData></worksheet>`); err != nil {
+		return fmt.Errorf("failed to write sheet: %w", err)
+	}
+	return nil
+}
+
+// sheetName removes characters which are not allowed in sheet names, shortens the name to 31 characters
+// and appends a number if the name is already used, as sheet names are unique ignoring case.
+func (w *Writer) sheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case '[', ']', ':', '*', '?', '/', '\\':
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.Trim(strings.TrimSpace(name), "'")
+	if name == "" {
+		name = fmt.Sprintf("Sheet%d", len(w.sheets)+1)
+	}
+
+	unique := truncate(name, maxSheetNameLength)
+	for i := 2; w.hasSheet(unique); i++ {
+		suffix := fmt.Sprintf(" (%d)", i)
+		unique = truncate(name, maxSheetNameLength-len(suffix)) + suffix
+	}
+	return unique
+}
+
+func (w *Writer) hasSheet(name string) bool {
+	for _, sheet := range w.sheets {
+		if strings.EqualFold(sheet, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+func escapeAttr(s string) string {
+	var sb strings.Builder
+	_ = xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
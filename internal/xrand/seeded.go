@@ -0,0 +1,56 @@
+package xrand
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"strconv"
+)
+
+// NewSeed returns a new hex encoded 32 byte seed from crypto/rand.
+func NewSeed() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// SeedCommitment returns the hex encoded SHA-256 hash of the seed string.
+// It can be published before the seed is used without revealing the seed.
+func SeedCommitment(seed string) string {
+	sum := sha256.Sum256([]byte(seed))
+	return hex.EncodeToString(sum[:])
+}
+
+// Seeded is a deterministic random number generator.
+// The n-th value is the first 8 bytes of SHA-256("<seed>:<n>") read as a big-endian uint64,
+// so anybody who knows the seed can reproduce every value with standard tools.
+type Seeded struct {
+	seed    string
+	counter uint64
+}
+
+func NewSeeded(seed string) *Seeded {
+	return &Seeded{seed: seed}
+}
+
+func (s *Seeded) Uint64() uint64 {
+	sum := sha256.Sum256([]byte(s.seed + ":" + strconv.FormatUint(s.counter, 10)))
+	s.counter++
+	return binary.BigEndian.Uint64(sum[:8])
+}
+
+// N returns a uniform number in [0, n). Values below 2^64 mod n are skipped to avoid modulo bias.
+// It panics if n <= 0.
+func (s *Seeded) N(n int) int {
+	if n <= 0 {
+		panic("invalid argument to N")
+	}
+	threshold := -uint64(n) % uint64(n)
+	for {
+		v := s.Uint64()
+		if v >= threshold {
+			return int(v % uint64(n))
+		}
+	}
+}
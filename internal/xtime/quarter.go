@@ -102,9 +102,14 @@ func GetRangeFromQuarter(value string) (time.Time, time.Time) {
 }
 
 func GetCurrentQuarterRange() (time.Time, time.Time) {
-	now := time.Now().UTC()
-	year := now.Year()
-	month := now.Month()
+	return GetQuarterRange(time.Now())
+}
+
+// GetQuarterRange returns the start and end of the quarter containing t.
+func GetQuarterRange(t time.Time) (time.Time, time.Time) {
+	t = t.UTC()
+	year := t.Year()
+	month := t.Month()
 
 	var startMonth time.Month
 	switch month {
@@ -118,7 +123,7 @@ func GetCurrentQuarterRange() (time.Time, time.Time) {
 		startMonth = time.October
 	}
 
-	start := time.Date(year, startMonth, 1, 0, 0, 0, 0, now.Location())
+	start := time.Date(year, startMonth, 1, 0, 0, 0, 0, t.Location())
 	end := start.AddDate(0, 3, -1).Add(time.Hour*23 + time.Minute*59 + time.Second*59) // End of the quarter
 
 	return start, end
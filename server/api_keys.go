@@ -0,0 +1,52 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/topi314/campfire-tools/internal/xrand"
+	"github.com/topi314/campfire-tools/server/database"
+)
+
+// APIKeyPrefix marks API keys so they are easy to recognize, e.g. by secret scanners.
+const APIKeyPrefix = "cft_"
+
+// NewAPIKey returns a new random API key. Only its hash is stored, the key itself is shown to the user once.
+func NewAPIKey() string {
+	return APIKeyPrefix + xrand.NewSeed()
+}
+
+// HashAPIKey returns the hex encoded SHA-256 hash of an API key.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func newAPIKeyLimiter() *APIKeyLimiter {
+	return &APIKeyLimiter{
+		limiters: make(map[int]*rate.Limiter),
+	}
+}
+
+// APIKeyLimiter rate limits requests per API key, allowing the configured number of requests per minute.
+type APIKeyLimiter struct {
+	mu       sync.Mutex
+	limiters map[int]*rate.Limiter
+}
+
+func (l *APIKeyLimiter) Allow(key database.APIKey) bool {
+	l.mu.Lock()
+	limiter, ok := l.limiters[key.ID]
+	if !ok {
+		perMinute := max(key.RateLimit, 1)
+		limiter = rate.NewLimiter(rate.Every(time.Minute/time.Duration(perMinute)), perMinute)
+		l.limiters[key.ID] = limiter
+	}
+	l.mu.Unlock()
+
+	return limiter.Allow()
+}
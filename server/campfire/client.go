@@ -12,13 +12,12 @@ import (
 	"net/http"
 	"strings"
 	"time"
-
-	"golang.org/x/time/rate"
 )
 
 const (
-	publicEndpoint = "https://niantic-social-api.nianticlabs.com/public/graphql"
-	endpoint       = "https://niantic-social-api.nianticlabs.com/graphql"
+	DefaultPublicEndpoint   = "https://niantic-social-api.nianticlabs.com/public/graphql"
+	DefaultEndpoint         = "https://niantic-social-api.nianticlabs.com/graphql"
+	DefaultShortURLEndpoint = "https://cmpf.re"
 )
 
 var (
@@ -29,27 +28,61 @@ var (
 	ErrEventNotFound    = errors.New("event not found")
 )
 
-type TokenFunc func(ctx context.Context) (string, error)
+func New(cfg Config, httpClient *http.Client, tokens TokensFunc) *Client {
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = DefaultEndpoint
+	}
+	if cfg.PublicEndpoint == "" {
+		cfg.PublicEndpoint = DefaultPublicEndpoint
+	}
+	if cfg.ShortURLEndpoint == "" {
+		cfg.ShortURLEndpoint = DefaultShortURLEndpoint
+	}
 
-func New(cfg Config, httpClient *http.Client, token TokenFunc) *Client {
 	return &Client{
 		cfg:        cfg,
 		httpClient: httpClient,
-		limiter:    rate.NewLimiter(rate.Every(time.Duration(cfg.Every)), cfg.Burst),
-		token:      token,
+		Tokens:     newTokenPool(cfg, tokens),
 	}
 }
 
 type Client struct {
 	cfg        Config
 	httpClient *http.Client
-	limiter    *rate.Limiter
-	token      TokenFunc
+	Tokens     *TokenPool
 }
 
-func (c *Client) Do(ctx context.Context, token string, query string, vars map[string]any, rsBody any) error {
+// Do runs an authenticated query with the next token from the pool.
+// Rate limited or rejected tokens fail over to the next token right away.
+func (c *Client) Do(ctx context.Context, query string, vars map[string]any, rsBody any) error {
 	for range c.cfg.MaxRetries {
-		if err := c.do(ctx, token, query, vars, rsBody); err != nil {
+		token, err := c.Tokens.acquire(ctx)
+		if err != nil {
+			return err
+		}
+
+		err = c.do(ctx, token.Token.Token, query, vars, rsBody)
+		c.Tokens.report(token, err)
+		if err != nil {
+			if errors.Is(err, ErrTooManyRequests) || errors.Is(err, ErrUnauthorized) {
+				continue
+			}
+			if errors.Is(err, ErrBadGateway) || errors.Is(err, ErrDeadlineExceeded) {
+				time.Sleep(time.Second)
+				continue
+			}
+			return err
+		}
+		return nil
+	}
+
+	return ErrTooManyRetries
+}
+
+// DoPublic runs a query which does not need a token.
+func (c *Client) DoPublic(ctx context.Context, query string, vars map[string]any, rsBody any) error {
+	for range c.cfg.MaxRetries {
+		if err := c.do(ctx, "", query, vars, rsBody); err != nil {
 			if errors.Is(err, ErrTooManyRequests) || errors.Is(err, ErrBadGateway) || errors.Is(err, ErrDeadlineExceeded) {
 				time.Sleep(time.Second)
 				continue
@@ -71,7 +104,7 @@ func (c *Client) do(ctx context.Context, token string, query string, vars map[st
 		return err
 	}
 
-	rq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, buff)
+	rq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.Endpoint, buff)
 	if err != nil {
 		return err
 	}
@@ -92,6 +125,8 @@ func (c *Client) do(ctx context.Context, token string, query string, vars map[st
 	switch rs.StatusCode {
 	case http.StatusTooManyRequests:
 		return ErrTooManyRequests
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthorized
 	case http.StatusBadGateway:
 		return ErrBadGateway
 	case http.StatusOK:
@@ -115,17 +150,31 @@ func (c *Client) do(ctx context.Context, token string, query string, vars map[st
 		var (
 			errs             []any
 			deadlineExceeded bool
+			unauthorized     bool
+			eventNotFound    error
 		)
 		for _, e := range resp.Errors {
 			if strings.Contains(e.Message, "DeadlineExceeded") {
 				deadlineExceeded = true
 			}
+			if isAuthError(e.Message) {
+				unauthorized = true
+			}
+			if e.Message == "event not found" {
+				eventNotFound = fmt.Errorf("%w: %w", ErrEventNotFound, e)
+			}
 			errs = append(errs, slog.String("message", e.String()))
 		}
 		slog.ErrorContext(ctx, "GraphQL errors", errs...)
 		if deadlineExceeded {
 			return ErrDeadlineExceeded
 		}
+		if unauthorized {
+			return ErrUnauthorized
+		}
+		if eventNotFound != nil {
+			return eventNotFound
+		}
 	}
 
 	if err = json.Unmarshal(resp.Data, rsBody); err != nil {
@@ -134,3 +183,8 @@ func (c *Client) do(ctx context.Context, token string, query string, vars map[st
 
 	return nil
 }
+
+func isAuthError(message string) bool {
+	message = strings.ToLower(message)
+	return strings.Contains(message, "unauthenticated") || strings.Contains(message, "unauthorized") || strings.Contains(message, "permission denied")
+}
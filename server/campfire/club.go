@@ -12,13 +12,8 @@ import (
 var clubQuery string
 
 func (c *Client) GetClub(ctx context.Context, id string) (*Club, error) {
-	token, err := c.token(ctx)
-	if err != nil {
-		return nil, err
-	}
-
 	var club clubResp
-	if err = c.Do(ctx, token, clubQuery, map[string]any{
+	if err := c.Do(ctx, clubQuery, map[string]any{
 		"clubId": id,
 	}, &club); err != nil {
 		return nil, err
@@ -7,15 +7,25 @@ import (
 )
 
 type Config struct {
-	Every      xtime.Duration `toml:"every"`
-	Burst      int            `toml:"burst"`
-	MaxRetries int            `toml:"max_retries"`
+	Endpoint             string         `toml:"endpoint"`
+	PublicEndpoint       string         `toml:"public_endpoint"`
+	ShortURLEndpoint     string         `toml:"short_url_endpoint"`
+	Every                xtime.Duration `toml:"every"`
+	Burst                int            `toml:"burst"`
+	MaxRetries           int            `toml:"max_retries"`
+	TokenCooldown        xtime.Duration `toml:"token_cooldown"`
+	TokenMaxAuthFailures int            `toml:"token_max_auth_failures"`
 }
 
 func (c Config) String() string {
-	return fmt.Sprintf("\n Every: %s\n Burst: %d\n MaxRetries: %d",
+	return fmt.Sprintf("\n Endpoint: %s\n PublicEndpoint: %s\n ShortURLEndpoint: %s\n Every: %s\n Burst: %d\n MaxRetries: %d\n TokenCooldown: %s\n TokenMaxAuthFailures: %d",
+		c.Endpoint,
+		c.PublicEndpoint,
+		c.ShortURLEndpoint,
 		c.Every,
 		c.Burst,
 		c.MaxRetries,
+		c.TokenCooldown,
+		c.TokenMaxAuthFailures,
 	)
 }
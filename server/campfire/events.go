@@ -22,11 +22,6 @@ var (
 )
 
 func (c *Client) GetPastEvents(ctx context.Context, clubID string, initialCursor *string) ([]Event, *string, error) {
-	token, err := c.token(ctx)
-	if err != nil {
-		return nil, nil, err
-	}
-
 	var allEvents []Event
 	var cursor *string
 	if initialCursor != nil {
@@ -35,7 +30,7 @@ func (c *Client) GetPastEvents(ctx context.Context, clubID string, initialCursor
 
 	for {
 		var club archivedFeedResp
-		if err = c.Do(ctx, token, archivedEventsQuery, map[string]any{
+		if err := c.Do(ctx, archivedEventsQuery, map[string]any{
 			"clubId": clubID,
 			"first":  eventsPerPage,
 			"after":  cursor,
@@ -57,11 +52,6 @@ func (c *Client) GetPastEvents(ctx context.Context, clubID string, initialCursor
 }
 
 func (c *Client) GetFutureEvents(ctx context.Context, clubID string, initialCursor *string) ([]Event, *string, error) {
-	token, err := c.token(ctx)
-	if err != nil {
-		return nil, nil, err
-	}
-
 	var allEvents []Event
 	var cursor *string
 	if initialCursor != nil {
@@ -70,7 +60,7 @@ func (c *Client) GetFutureEvents(ctx context.Context, clubID string, initialCurs
 
 	for {
 		var club activeFeedResp
-		if err = c.Do(ctx, token, activeEventsQuery, map[string]any{
+		if err := c.Do(ctx, activeEventsQuery, map[string]any{
 			"clubId": clubID,
 			"first":  eventsPerPage,
 			"after":  cursor,
@@ -102,7 +92,7 @@ func (c *Client) GetEventMembers(ctx context.Context, eventID string, initialCur
 
 	for {
 		var event eventResp
-		if err := c.Do(ctx, "", eventMembersQuery, map[string]any{
+		if err := c.DoPublic(ctx, eventMembersQuery, map[string]any{
 			"eventId": eventID,
 			"first":   membersPerPage,
 			"after":   memberCursor,
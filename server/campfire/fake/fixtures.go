@@ -0,0 +1,70 @@
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/topi314/campfire-tools/server/campfire"
+)
+
+// Fixtures is the data served by the fake. Clubs and events use the same JSON shape as the real Campfire API,
+// so captured responses can be pasted in as they are.
+type Fixtures struct {
+	Clubs  []campfire.Club  `json:"clubs"`
+	Events []campfire.Event `json:"events"`
+	// PublicIDs maps public meetup IDs (as used in https://niantic-social.nianticlabs.com/public/meetup/<id>) to event IDs.
+	// Events without an entry are reachable by their own ID.
+	PublicIDs map[string]string `json:"public_ids"`
+	// ShortURLs maps short URL codes (as used in https://cmpf.re/<code>) to public meetup IDs.
+	ShortURLs map[string]string `json:"short_urls"`
+}
+
+func LoadFixtures(path string) (*Fixtures, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixtures: %w", err)
+	}
+
+	var fixtures Fixtures
+	if err = json.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("failed to parse fixtures: %w", err)
+	}
+
+	return &fixtures, nil
+}
+
+func (f *Fixtures) club(id string) (campfire.Club, bool) {
+	for _, club := range f.Clubs {
+		if club.ID == id {
+			return club, true
+		}
+	}
+	return campfire.Club{}, false
+}
+
+func (f *Fixtures) event(id string) (campfire.Event, bool) {
+	for _, event := range f.Events {
+		if event.ID == id {
+			return event, true
+		}
+	}
+	return campfire.Event{}, false
+}
+
+func (f *Fixtures) clubEvents(clubID string) []campfire.Event {
+	var events []campfire.Event
+	for _, event := range f.Events {
+		if event.ClubID == clubID || event.Club.ID == clubID {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+func (f *Fixtures) publicEventID(publicID string) string {
+	if eventID, ok := f.PublicIDs[publicID]; ok {
+		return eventID
+	}
+	return publicID
+}
@@ -0,0 +1,252 @@
+// Package fake implements a minimal stand-in for the Campfire GraphQL API.
+// It answers the operations in server/campfire/queries from fixture data, so importers, raffles and exports can run offline.
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/topi314/campfire-tools/server/campfire"
+)
+
+var operationRegex = regexp.MustCompile(`(?:query|mutation)\s+(\w+)`)
+
+// New returns a handler serving the private API on /graphql, the public API on /public/graphql and short URLs on /s/{code}.
+// Point campfire.Config.Endpoint, PublicEndpoint and ShortURLEndpoint (the latter with the /s suffix) at it.
+func New(fixtures *Fixtures) http.Handler {
+	s := &server{
+		fixtures: fixtures,
+		now:      time.Now,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /graphql", s.graphql)
+	mux.HandleFunc("POST /public/graphql", s.graphql)
+	mux.HandleFunc("GET /s/{code}", s.shortURL)
+	return mux
+}
+
+type server struct {
+	fixtures *Fixtures
+	now      func() time.Time
+}
+
+func (s *server) graphql(w http.ResponseWriter, r *http.Request) {
+	var rq campfire.Req
+	if err := json.NewDecoder(r.Body).Decode(&rq); err != nil {
+		http.Error(w, "Failed to decode request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var operation string
+	if matches := operationRegex.FindStringSubmatch(rq.Query); len(matches) > 1 {
+		operation = matches[1]
+	}
+
+	slog.DebugContext(r.Context(), "Fake GraphQL request", slog.String("operation", operation), slog.String("variables", fmt.Sprintf("%+v", rq.Variables)))
+
+	var (
+		data any
+		errs []campfire.Error
+	)
+	switch operation {
+	case "Club_Query":
+		data, errs = s.club(rq.Variables)
+	case "ArchivedEvents_Query":
+		data, errs = s.feed(rq.Variables, "archivedFeed", func(event campfire.Event) bool {
+			return !event.EventEndTime.After(s.now())
+		})
+	case "ActiveEvents_Query":
+		data, errs = s.feed(rq.Variables, "activeFeed", func(event campfire.Event) bool {
+			return event.EventEndTime.After(s.now())
+		})
+	case "Event_Query":
+		data, errs = s.event(rq.Variables)
+	case "ArchivedMeetupsMembers_Query":
+		data, errs = s.eventMembers(rq.Variables)
+	case "PublicMeetups_Query":
+		data, errs = s.publicMeetups(rq.Variables)
+	default:
+		errs = []campfire.Error{{Message: "unsupported operation: " + operation}}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(campfire.Resp[any]{
+		Errors: errs,
+		Data:   data,
+	}); err != nil {
+		slog.ErrorContext(r.Context(), "Failed to encode response", slog.Any("err", err))
+	}
+}
+
+func (s *server) club(vars map[string]any) (any, []campfire.Error) {
+	club, ok := s.fixtures.club(stringVar(vars, "clubId"))
+	if !ok {
+		return nil, []campfire.Error{{Message: "club not found", Path: []any{"club"}}}
+	}
+
+	return map[string]any{
+		"club": club,
+	}, nil
+}
+
+func (s *server) feed(vars map[string]any, name string, include func(event campfire.Event) bool) (any, []campfire.Error) {
+	clubID := stringVar(vars, "clubId")
+	if _, ok := s.fixtures.club(clubID); !ok {
+		return nil, []campfire.Error{{Message: "club not found", Path: []any{"club"}}}
+	}
+
+	var events []campfire.Event
+	for _, event := range s.fixtures.clubEvents(clubID) {
+		if !include(event) {
+			continue
+		}
+		// members are fetched separately via ArchivedMeetupsMembers_Query
+		event.Members = campfire.Pagination[campfire.Member]{}
+		events = append(events, event)
+	}
+
+	return map[string]any{
+		"club": map[string]any{
+			"id": clubID,
+			name: paginate(events, vars),
+		},
+	}, nil
+}
+
+func (s *server) event(vars map[string]any) (any, []campfire.Error) {
+	event, ok := s.fixtures.event(stringVar(vars, "id"))
+	if !ok {
+		return map[string]any{"event": nil}, []campfire.Error{{Message: "event not found", Path: []any{"event"}}}
+	}
+
+	var members []campfire.Member
+	for _, edge := range event.Members.Edges {
+		members = append(members, edge.Node)
+	}
+	event.Members = paginate(members, vars)
+
+	return map[string]any{
+		"event": event,
+	}, nil
+}
+
+func (s *server) eventMembers(vars map[string]any) (any, []campfire.Error) {
+	event, ok := s.fixtures.event(stringVar(vars, "eventId"))
+	if !ok {
+		return map[string]any{"event": nil}, []campfire.Error{{Message: "event not found", Path: []any{"event"}}}
+	}
+
+	var members []campfire.Member
+	for _, edge := range event.Members.Edges {
+		members = append(members, edge.Node)
+	}
+
+	return map[string]any{
+		"event": map[string]any{
+			"id":      event.ID,
+			"members": paginate(members, vars),
+		},
+	}, nil
+}
+
+func (s *server) publicMeetups(vars map[string]any) (any, []campfire.Error) {
+	ids, _ := vars["ids"].([]any)
+
+	objects := make([]map[string]any, 0, len(ids))
+	for _, rawID := range ids {
+		publicID, _ := rawID.(string)
+		event, ok := s.fixtures.event(s.fixtures.publicEventID(publicID))
+		if !ok {
+			continue
+		}
+
+		clubID := event.ClubID
+		if clubID == "" {
+			clubID = event.Club.ID
+		}
+		objects = append(objects, map[string]any{
+			"id": publicID,
+			"event": map[string]any{
+				"id":                       event.ID,
+				"name":                     event.Name,
+				"details":                  event.Details,
+				"clubName":                 event.Club.Name,
+				"clubId":                   clubID,
+				"clubAvatarUrl":            event.Club.AvatarURL,
+				"isPasscodeRewardEligible": event.IsPasscodeRewardEligible,
+				"place":                    nil,
+				"mapObjectLocation": map[string]any{
+					"latitude":  0,
+					"longitude": 0,
+				},
+				"eventTime":    event.EventTime,
+				"eventEndTime": event.EventEndTime,
+				"address":      event.Address,
+			},
+		})
+	}
+
+	return map[string]any{
+		"publicMapObjectsById": objects,
+	}, nil
+}
+
+func (s *server) shortURL(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := s.fixtures.ShortURLs[r.PathValue("code")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Location", "https://niantic-social.nianticlabs.com/public/meetup/"+eventID)
+	w.WriteHeader(http.StatusTemporaryRedirect)
+}
+
+// paginate mimics the relay style pagination of the Campfire API. Cursors are plain offsets.
+func paginate[T any](nodes []T, vars map[string]any) campfire.Pagination[T] {
+	first := len(nodes)
+	if f, ok := vars["first"].(float64); ok && int(f) < first {
+		first = int(f)
+	}
+
+	var start int
+	if after := stringVar(vars, "after"); after != "" {
+		if i, err := strconv.Atoi(after); err == nil {
+			start = i + 1
+		}
+	}
+	start = min(start, len(nodes))
+	end := min(start+first, len(nodes))
+
+	page := campfire.Pagination[T]{
+		TotalCount: len(nodes),
+		Edges:      make([]campfire.Edge[T], 0, end-start),
+		PageInfo: campfire.PageInfo{
+			HasNextPage: end < len(nodes),
+		},
+	}
+	for i := start; i < end; i++ {
+		page.Edges = append(page.Edges, campfire.Edge[T]{
+			Node:   nodes[i],
+			Cursor: strconv.Itoa(i),
+		})
+	}
+	if len(page.Edges) > 0 {
+		page.PageInfo.StartCursor = page.Edges[0].Cursor
+		page.PageInfo.EndCursor = page.Edges[len(page.Edges)-1].Cursor
+	}
+
+	return page
+}
+
+func stringVar(vars map[string]any, name string) string {
+	v, _ := vars[name].(string)
+	return strings.TrimSpace(v)
+}
@@ -8,7 +8,16 @@ import (
 	"time"
 )
 
-const BadgeGrantMarkedCAClub = "MARKED_CA_CLUB"
+const (
+	BadgeGrantMarkedCAClub        = "MARKED_CA_CLUB"
+	BadgeAliasCommunityAmbassador = "PGO_COMMUNITY_AMBASSADOR"
+)
+
+func MemberIsCommunityAmbassador(badges []Badge) bool {
+	return slices.ContainsFunc(badges, func(badge Badge) bool {
+		return badge.Alias == BadgeAliasCommunityAmbassador
+	})
+}
 
 func ClubCreatedByCommunityAmbassador(createdByCA bool, badgeGrants []string) bool {
 	return createdByCA || slices.Contains(badgeGrants, BadgeGrantMarkedCAClub)
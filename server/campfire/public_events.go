@@ -20,13 +20,6 @@ var publicEventsQuery string
 var ErrUnsupportedMeetup = errors.New("meetup not supported")
 
 func (c *Client) ResolveEventID(ctx context.Context, meetupURL string) (string, error) {
-	if err := c.limiter.Wait(ctx); err != nil {
-		return "", err
-	}
-	return c.resolveEventID(ctx, meetupURL)
-}
-
-func (c *Client) resolveEventID(ctx context.Context, meetupURL string) (string, error) {
 	if strings.HasPrefix(meetupURL, "https://niantic-social.nianticlabs.com/public/meetup-without-location/") {
 		return "", ErrUnsupportedMeetup
 	}
@@ -36,7 +29,7 @@ func (c *Client) resolveEventID(ctx context.Context, meetupURL string) (string,
 		if err != nil {
 			return "", fmt.Errorf("failed to resolve short URL: %w", err)
 		}
-		return c.resolveEventID(ctx, newMeetupURL)
+		return c.ResolveEventID(ctx, newMeetupURL)
 	}
 
 	var campfireEventID string
@@ -106,7 +99,7 @@ func (c *Client) getEvents(ctx context.Context, eventIDs []string, try int) (*Ev
 		return nil, fmt.Errorf("failed to encode request body: %w", err)
 	}
 
-	rq, err := http.NewRequestWithContext(ctx, http.MethodPost, publicEndpoint, buf)
+	rq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.PublicEndpoint, buf)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -137,3 +130,8 @@ func (c *Client) getEvents(ctx context.Context, eventIDs []string, try int) (*Ev
 
 	return &resp.Data, nil
 }
+
+// EventURL returns the Campfire link of the event.
+func EventURL(eventID string) string {
+	return "https://campfire.nianticlabs.com/discover/meetup/" + eventID
+}
@@ -0,0 +1,203 @@
+package campfire
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const tokenPoolRefreshInterval = time.Minute
+
+var (
+	ErrNoTokens     = errors.New("oooops, no valid token found. Please ping me on Discord with this error")
+	ErrUnauthorized = errors.New("unauthorized")
+)
+
+// Token is a Campfire access token which can be used by the TokenPool.
+type Token struct {
+	ID        int
+	Token     string
+	ExpiresAt time.Time
+}
+
+// TokensFunc returns all currently valid tokens.
+type TokensFunc func(ctx context.Context) ([]Token, error)
+
+// TokenStats is a snapshot of the usage and health of a token in the TokenPool.
+type TokenStats struct {
+	ID            int
+	Requests      int
+	RateLimited   int
+	AuthFailures  int
+	Healthy       bool
+	CooldownUntil time.Time
+	LastUsedAt    time.Time
+	LastError     string
+}
+
+type pooledToken struct {
+	Token
+	limiter       *rate.Limiter
+	requests      int
+	rateLimited   int
+	authFailures  int
+	unhealthy     bool
+	cooldownUntil time.Time
+	lastUsedAt    time.Time
+	lastError     string
+}
+
+func newTokenPool(cfg Config, tokens TokensFunc) *TokenPool {
+	return &TokenPool{
+		cfg:    cfg,
+		load:   tokens,
+		tokens: map[int]*pooledToken{},
+	}
+}
+
+// TokenPool rotates requests round-robin over all valid tokens. Every token has its own rate limiter,
+// rate limited tokens cool down before they are used again and tokens with repeated auth failures are skipped.
+type TokenPool struct {
+	cfg  Config
+	load TokensFunc
+
+	mu       sync.Mutex
+	tokens   map[int]*pooledToken
+	order    []int
+	next     int
+	loadedAt time.Time
+}
+
+// acquire picks the next usable token and waits for its rate limiter.
+func (p *TokenPool) acquire(ctx context.Context) (*pooledToken, error) {
+	token, err := p.pick(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = token.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func (p *TokenPool) pick(ctx context.Context) (*pooledToken, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	if len(p.order) == 0 || now.Sub(p.loadedAt) >= tokenPoolRefreshInterval {
+		if err := p.refresh(ctx, now); err != nil {
+			return nil, err
+		}
+	}
+
+	var coolingDown bool
+	for range len(p.order) {
+		token := p.tokens[p.order[p.next%len(p.order)]]
+		p.next = (p.next + 1) % len(p.order)
+
+		if token.unhealthy || !token.ExpiresAt.After(now.Add(time.Minute)) {
+			continue
+		}
+		if token.cooldownUntil.After(now) {
+			coolingDown = true
+			continue
+		}
+
+		token.requests++
+		token.lastUsedAt = now
+		return token, nil
+	}
+
+	if coolingDown {
+		return nil, ErrTooManyRequests
+	}
+	return nil, ErrNoTokens
+}
+
+// refresh reloads the tokens while keeping the state of tokens which are still valid.
+// Unhealthy tokens become healthy again once their value or expiry changed. p.mu must be held.
+func (p *TokenPool) refresh(ctx context.Context, now time.Time) error {
+	tokens, err := p.load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load campfire tokens: %w", err)
+	}
+	p.loadedAt = now
+
+	pooled := make(map[int]*pooledToken, len(tokens))
+	order := make([]int, 0, len(tokens))
+	for _, token := range tokens {
+		if existing, ok := p.tokens[token.ID]; ok {
+			// a new token value or expiry means the token was renewed, so its auth failures do not apply anymore
+			if existing.Token.Token != token.Token || !existing.ExpiresAt.Equal(token.ExpiresAt) {
+				existing.Token = token
+				existing.authFailures = 0
+				existing.unhealthy = false
+				existing.lastError = ""
+			}
+			pooled[token.ID] = existing
+		} else {
+			pooled[token.ID] = &pooledToken{
+				Token:   token,
+				limiter: rate.NewLimiter(rate.Every(time.Duration(p.cfg.Every)), p.cfg.Burst),
+			}
+		}
+		order = append(order, token.ID)
+	}
+	slices.Sort(order)
+
+	p.tokens = pooled
+	p.order = order
+	return nil
+}
+
+// report updates the health of a token with the result of a request made with it.
+func (p *TokenPool) report(token *pooledToken, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch {
+	case err == nil:
+		token.authFailures = 0
+		token.lastError = ""
+	case errors.Is(err, ErrTooManyRequests):
+		token.rateLimited++
+		token.cooldownUntil = time.Now().Add(time.Duration(p.cfg.TokenCooldown))
+		token.lastError = err.Error()
+	case errors.Is(err, ErrUnauthorized):
+		token.authFailures++
+		if token.authFailures >= max(p.cfg.TokenMaxAuthFailures, 1) {
+			token.unhealthy = true
+		}
+		token.lastError = err.Error()
+	}
+}
+
+// Stats returns the usage and health of all tokens currently in the pool.
+func (p *TokenPool) Stats() []TokenStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := make([]TokenStats, 0, len(p.order))
+	for _, id := range p.order {
+		token := p.tokens[id]
+		stats = append(stats, TokenStats{
+			ID:            token.ID,
+			Requests:      token.requests,
+			RateLimited:   token.rateLimited,
+			AuthFailures:  token.authFailures,
+			Healthy:       !token.unhealthy,
+			CooldownUntil: token.cooldownUntil,
+			LastUsedAt:    token.lastUsedAt,
+			LastError:     token.lastError,
+		})
+	}
+	return stats
+}
@@ -0,0 +1,237 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/disgo/webhook"
+
+	"github.com/topi314/campfire-tools/internal/xtime"
+	"github.com/topi314/campfire-tools/server/database"
+	"github.com/topi314/campfire-tools/server/web/models"
+)
+
+const (
+	clubDigestTopEvents  = 3
+	clubDigestTopMembers = 5
+)
+
+type clubDigestPeriod string
+
+const (
+	clubDigestWeekly    clubDigestPeriod = "weekly"
+	clubDigestQuarterly clubDigestPeriod = "quarterly"
+)
+
+// clubDigestJobs returns the digest jobs which are currently due for the given clubs.
+func (s *Server) clubDigestJobs(clubs []database.Club, now time.Time) []database.Job {
+	var jobs []database.Job
+	for _, club := range clubs {
+		if club.DigestWeekly && club.LastWeeklyDigestAt.Before(lastWeeklyDigestAt(club, now)) {
+			jobs = append(jobs, s.NewJob(database.JobKindSendClubDigest, club.ID+":"+string(clubDigestWeekly)))
+		}
+		if club.DigestQuarterly && club.LastQuarterlyDigestAt.Before(lastQuarterlyDigestAt(club, now)) {
+			jobs = append(jobs, s.NewJob(database.JobKindSendClubDigest, club.ID+":"+string(clubDigestQuarterly)))
+		}
+	}
+	return jobs
+}
+
+// lastWeeklyDigestAt returns the most recent time at or before now the weekly digest of the club was scheduled for.
+func lastWeeklyDigestAt(club database.Club, now time.Time) time.Time {
+	now = now.UTC()
+	t := time.Date(now.Year(), now.Month(), now.Day(), club.DigestHour, 0, 0, 0, time.UTC)
+	t = t.AddDate(0, 0, -int((now.Weekday()-club.DigestWeekday+7)%7))
+	if t.After(now) {
+		t = t.AddDate(0, 0, -7)
+	}
+	return t
+}
+
+// lastQuarterlyDigestAt returns the most recent time at or before now the quarterly digest of the club was scheduled for.
+// Quarterly digests are sent on the first day of a quarter and cover the previous quarter.
+func lastQuarterlyDigestAt(club database.Club, now time.Time) time.Time {
+	from, _ := xtime.GetQuarterRange(now)
+	t := from.Add(time.Duration(club.DigestHour) * time.Hour)
+	if t.After(now) {
+		from, _ = xtime.GetQuarterRange(from.AddDate(0, 0, -1))
+		t = from.Add(time.Duration(club.DigestHour) * time.Hour)
+	}
+	return t
+}
+
+func (s *Server) runSendClubDigestJob(ctx context.Context, job database.Job) error {
+	clubID, period, ok := strings.Cut(job.Key, ":")
+	if !ok {
+		return fmt.Errorf("invalid club digest job key: %s", job.Key)
+	}
+
+	club, err := s.DB.GetClub(ctx, clubID)
+	if err != nil {
+		return fmt.Errorf("failed to get club: %w", err)
+	}
+
+	// the digest might have been disabled since the job was queued
+	if club.DigestWebhookURL == nil {
+		return nil
+	}
+
+	now := time.Now()
+	var (
+		content     string
+		scheduledAt time.Time
+		lastAt      time.Time
+		claim       func(ctx context.Context, clubID string, scheduledAt time.Time) (bool, error)
+		updateLast  func(ctx context.Context, clubID string, at time.Time) error
+	)
+	switch clubDigestPeriod(period) {
+	case clubDigestWeekly:
+		if !club.DigestWeekly {
+			return nil
+		}
+		scheduledAt = lastWeeklyDigestAt(club.Club, now)
+		lastAt = club.LastWeeklyDigestAt
+		content, err = s.buildClubDigest(ctx, club.Club, clubDigestWeekly, scheduledAt.AddDate(0, 0, -7), scheduledAt, now)
+		claim = s.DB.ClaimClubWeeklyDigest
+		updateLast = s.DB.UpdateClubLastWeeklyDigest
+	case clubDigestQuarterly:
+		if !club.DigestQuarterly {
+			return nil
+		}
+		scheduledAt = lastQuarterlyDigestAt(club.Club, now)
+		lastAt = club.LastQuarterlyDigestAt
+		from, to := xtime.GetQuarterRange(scheduledAt.AddDate(0, 0, -1))
+		content, err = s.buildClubDigest(ctx, club.Club, clubDigestQuarterly, from, to, now)
+		claim = s.DB.ClaimClubQuarterlyDigest
+		updateLast = s.DB.UpdateClubLastQuarterlyDigest
+	default:
+		return fmt.Errorf("invalid club digest period: %s", period)
+	}
+	if err != nil {
+		return err
+	}
+
+	client, err := webhook.NewWithURL(*club.DigestWebhookURL)
+	if err != nil {
+		return fmt.Errorf("invalid club digest webhook URL: %w", err)
+	}
+	defer client.Close(context.WithoutCancel(ctx))
+
+	// record the digest as sent before posting it, so a retried or duplicate job does not post it twice
+	claimed, err := claim(ctx, clubID, scheduledAt)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return nil
+	}
+
+	if _, err = client.CreateMessage(discord.WebhookMessageCreate{
+		Flags: discord.MessageFlagIsComponentsV2,
+		Components: []discord.LayoutComponent{
+			discord.NewContainer(
+				discord.NewTextDisplay(content),
+			).WithAccentColor(0xfe812e),
+		},
+	}, rest.CreateWebhookMessageParams{
+		WithComponents: true,
+	}, rest.WithCtx(ctx)); err != nil {
+		// release the claim so the retried job sends the digest
+		if updateErr := updateLast(context.WithoutCancel(ctx), clubID, lastAt); updateErr != nil {
+			slog.ErrorContext(ctx, "Failed to reset club last digest", slog.String("club_id", clubID), slog.String("period", period), slog.Any("err", updateErr))
+		}
+		return fmt.Errorf("failed to send club digest: %w", err)
+	}
+
+	slog.InfoContext(ctx, "Sent club digest", slog.String("club_id", clubID), slog.String("period", period))
+
+	return nil
+}
+
+// buildClubDigest renders the digest message for the given range. The league progress is calculated for the quarter the range ends in.
+func (s *Server) buildClubDigest(ctx context.Context, club database.Club, period clubDigestPeriod, from time.Time, to time.Time, now time.Time) (string, error) {
+	_, checkIns, err := s.DB.GetClubTotalCheckInsAccepted(ctx, club.ID, from, to, false, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to get club check-ins: %w", err)
+	}
+
+	_, caCheckIns, err := s.DB.GetClubTotalCheckInsAccepted(ctx, club.ID, from, to, true, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to get club CA check-ins: %w", err)
+	}
+
+	quarterFrom, quarterTo := xtime.GetQuarterRange(to.Add(-time.Second))
+	_, quarterCACheckIns, err := s.DB.GetClubTotalCheckInsAccepted(ctx, club.ID, quarterFrom, quarterTo, true, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to get club quarter CA check-ins: %w", err)
+	}
+
+	// the projection needs at least one elapsed day to extrapolate from
+	projectedCACheckIns := quarterCACheckIns
+	if now.Sub(quarterFrom) >= 24*time.Hour {
+		projectedCACheckIns, _, _ = models.CalcCAProjectedCheckIns(quarterFrom, quarterTo, quarterCACheckIns)
+	}
+
+	topEvents, err := s.DB.GetTopEventsByClub(ctx, club.ID, from, to, false, "", clubDigestTopEvents)
+	if err != nil {
+		return "", fmt.Errorf("failed to get club top events: %w", err)
+	}
+
+	topMembers, err := s.DB.GetTopMembersByClub(ctx, club.ID, from, to, false, "", clubDigestTopMembers)
+	if err != nil {
+		return "", fmt.Errorf("failed to get club top members: %w", err)
+	}
+
+	quarter := fmt.Sprintf("Q%d %d", (int(quarterFrom.Month())-1)/3+1, quarterFrom.Year())
+
+	var b strings.Builder
+	switch period {
+	case clubDigestWeekly:
+		fmt.Fprintf(&b, "## Weekly Digest: %s\n", club.Name)
+		fmt.Fprintf(&b, "-# %s - %s\n", from.Format(time.DateOnly), to.Add(-time.Second).Format(time.DateOnly))
+		fmt.Fprintf(&b, "**Check-ins this week:** %d (%d CA)\n", checkIns, caCheckIns)
+	case clubDigestQuarterly:
+		fmt.Fprintf(&b, "## Quarterly Digest: %s\n", club.Name)
+		fmt.Fprintf(&b, "-# %s\n", quarter)
+		fmt.Fprintf(&b, "**Check-ins this quarter:** %d (%d CA)\n", checkIns, caCheckIns)
+	}
+
+	fmt.Fprintf(&b, "\n### League Progress %s\n", quarter)
+	fmt.Fprintf(&b, "**CA check-ins:** %d, projected %d\n", quarterCACheckIns, projectedCACheckIns)
+	for _, league := range models.Leagues {
+		status := "⬜"
+		if quarterCACheckIns >= league.Goal {
+			status = "✅"
+		} else if projectedCACheckIns >= league.Goal {
+			status = "📈"
+		}
+		fmt.Fprintf(&b, "%s %s: %d/%d (%.0f%%)\n", status, league.Name, min(quarterCACheckIns, league.Goal), league.Goal, models.CalcCheckInProgress(league.Goal, quarterCACheckIns))
+	}
+
+	b.WriteString("\n### Top Events\n")
+	if len(topEvents) == 0 {
+		b.WriteString("No events\n")
+	}
+	for i, event := range topEvents {
+		fmt.Fprintf(&b, "%d. %s: %d check-ins\n", i+1, event.Name, event.CheckIns)
+	}
+
+	b.WriteString("\n### Top Members\n")
+	if len(topMembers) == 0 {
+		b.WriteString("No members\n")
+	}
+	for i, member := range topMembers {
+		fmt.Fprintf(&b, "%d. %s: %d check-ins\n", i+1, models.GetDisplayName(member.DisplayName, member.Username), member.CheckIns)
+	}
+
+	if s.Cfg.Server.PublicTrackerURL != "" {
+		fmt.Fprintf(&b, "\n[View Club Stats](%s/tracker/club/%s/stats)", s.Cfg.Server.PublicTrackerURL, club.ID)
+	}
+
+	return b.String(), nil
+}
@@ -4,33 +4,22 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"log/slog"
 	"slices"
+	"strconv"
 	"time"
 
 	"github.com/topi314/campfire-tools/server/database"
 )
 
-var ErrContinueLater = errors.New("continue later")
-
-func (s *Server) importClubs() {
-	for {
-		s.doImportClubs()
-		time.Sleep(10 * time.Second)
-	}
-}
-
-func (s *Server) doImportClubs() {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
-	defer cancel()
-
-	if err := s.doImportNextClub(ctx); err != nil {
-		slog.ErrorContext(ctx, "Failed to import next club", slog.Any("err", err))
+func (s *Server) runImportClubJob(ctx context.Context, job database.Job) error {
+	clubImportJobID, err := strconv.Atoi(job.Key)
+	if err != nil {
+		return fmt.Errorf("invalid club import job id: %w", err)
 	}
-}
 
-func (s *Server) doImportNextClub(ctx context.Context) error {
-	job, err := s.DB.GetNextPendingClubImportJob(ctx)
+	clubImportJob, err := s.DB.GetClubImportJob(ctx, clubImportJobID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil
@@ -38,25 +27,32 @@ func (s *Server) doImportNextClub(ctx context.Context) error {
 		return err
 	}
 
-	slog.InfoContext(ctx, "Importing club", slog.Int("job_id", job.ID), slog.String("club_id", job.ClubID))
+	if clubImportJob.Status == database.ClubImportJobStatusCompleted {
+		return nil
+	}
 
-	job.LastTriedAt = time.Now()
-	state, err := s.importClubEvents(ctx, *job, job.State.V)
+	slog.InfoContext(ctx, "Importing club", slog.Int("job_id", clubImportJob.ID), slog.String("club_id", clubImportJob.ClubID))
+
+	clubImportJob.LastTriedAt = time.Now()
+	state, err := s.importClubEvents(ctx, *clubImportJob, clubImportJob.State.V)
 	if err != nil {
-		if errors.Is(err, ErrContinueLater) {
-			job.Status = database.ClubImportJobStatusPending
-		} else {
-			job.Status = database.ClubImportJobStatusFailed
-			job.Error = err.Error()
+		clubImportJob.Status = database.ClubImportJobStatusPending
+		if !errors.Is(err, ErrContinueLater) {
+			clubImportJob.Error = err.Error()
+			// only give up on the import once the job is out of attempts
+			if job.Attempts >= job.MaxAttempts {
+				clubImportJob.Status = database.ClubImportJobStatusFailed
+			}
 		}
 	} else {
-		job.Status = database.ClubImportJobStatusCompleted
-		job.CompletedAt = time.Now()
+		clubImportJob.Status = database.ClubImportJobStatusCompleted
+		clubImportJob.CompletedAt = time.Now()
+		clubImportJob.Error = ""
 	}
-	job.State.V = state
+	clubImportJob.State.V = state
 
-	if updateErr := s.DB.UpdateClubImportJob(context.WithoutCancel(ctx), *job); updateErr != nil {
-		slog.ErrorContext(ctx, "Failed to update club import job after failure", slog.Any("err", updateErr))
+	if updateErr := s.DB.UpdateClubImportJob(context.WithoutCancel(ctx), *clubImportJob); updateErr != nil {
+		slog.ErrorContext(ctx, "Failed to update club import job", slog.Any("err", updateErr))
 	}
 
 	return err
@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/BurntSushi/toml"
 
+	"github.com/topi314/campfire-tools/internal/s3"
 	"github.com/topi314/campfire-tools/internal/xtime"
 	"github.com/topi314/campfire-tools/server/auth"
 	"github.com/topi314/campfire-tools/server/campfire"
@@ -51,9 +53,22 @@ func defaultConfig() Config {
 			Database: "campfire-tools",
 		},
 		Campfire: campfire.Config{
-			Every:      xtime.Duration(1 * time.Second),
-			Burst:      40,
-			MaxRetries: 3,
+			Endpoint:             campfire.DefaultEndpoint,
+			PublicEndpoint:       campfire.DefaultPublicEndpoint,
+			ShortURLEndpoint:     campfire.DefaultShortURLEndpoint,
+			Every:                xtime.Duration(1 * time.Second),
+			Burst:                40,
+			MaxRetries:           3,
+			TokenCooldown:        xtime.Duration(1 * time.Minute),
+			TokenMaxAuthFailures: 3,
+		},
+		Jobs: JobsConfig{
+			Workers:      3,
+			PollInterval: xtime.Duration(10 * time.Second),
+			Timeout:      xtime.Duration(2 * time.Minute),
+			MaxAttempts:  5,
+			BackoffBase:  xtime.Duration(30 * time.Second),
+			BackoffMax:   xtime.Duration(1 * time.Hour),
 		},
 	}
 }
@@ -65,22 +80,26 @@ type Config struct {
 	Server                     ServerConfig        `toml:"server"`
 	Database                   database.Config     `toml:"database"`
 	Campfire                   campfire.Config     `toml:"campfire"`
+	Jobs                       JobsConfig          `toml:"jobs"`
 	DiscordAuth                auth.Config         `toml:"discord_auth"`
 	CampfireAuth               cauth.Config        `toml:"campfire_auth"`
 	Notifications              NotificationsConfig `toml:"notifications"`
+	S3                         s3.Config           `toml:"s3"`
 }
 
 func (c Config) String() string {
-	return fmt.Sprintf("Dev: %t\nWarnUnknownEventCategories: %t\nLog: %s\nServer: %s\nDatabase: %s\nCampfire: %s\nDiscordAuth: %s\nCampfireAuth: %s\nNotifications: %s",
+	return fmt.Sprintf("Dev: %t\nWarnUnknownEventCategories: %t\nLog: %s\nServer: %s\nDatabase: %s\nCampfire: %s\nJobs: %s\nDiscordAuth: %s\nCampfireAuth: %s\nNotifications: %s\nS3: %s",
 		c.Dev,
 		c.WarnUnknownEventCategories,
 		c.Log,
 		c.Server,
 		c.Database,
 		c.Campfire,
+		c.Jobs,
 		c.DiscordAuth,
 		c.CampfireAuth,
 		c.Notifications,
+		c.S3,
 	)
 }
 
@@ -110,14 +129,16 @@ type ServerConfig struct {
 	PublicTrackerURL string `toml:"public_tracker_url"`
 	RewardsAddr      string `toml:"rewards_addr"`
 	PublicRewardsURL string `toml:"public_rewards_url"`
+	SigningSecret    string `toml:"signing_secret"`
 }
 
 func (c ServerConfig) String() string {
-	return fmt.Sprintf("\n TrackerAddr: %s\n PublicTrackerURL: %s\n RewardsAddr: %s\n PublicRewardsURL: %s",
+	return fmt.Sprintf("\n TrackerAddr: %s\n PublicTrackerURL: %s\n RewardsAddr: %s\n PublicRewardsURL: %s\n SigningSecret: %s",
 		c.TrackerAddr,
 		c.PublicTrackerURL,
 		c.RewardsAddr,
 		c.PublicRewardsURL,
+		strings.Repeat("*", len(c.SigningSecret)),
 	)
 }
 
@@ -132,3 +153,23 @@ func (c NotificationsConfig) String() string {
 		c.WebhookURL,
 	)
 }
+
+type JobsConfig struct {
+	Workers      int            `toml:"workers"`
+	PollInterval xtime.Duration `toml:"poll_interval"`
+	Timeout      xtime.Duration `toml:"timeout"`
+	MaxAttempts  int            `toml:"max_attempts"`
+	BackoffBase  xtime.Duration `toml:"backoff_base"`
+	BackoffMax   xtime.Duration `toml:"backoff_max"`
+}
+
+func (c JobsConfig) String() string {
+	return fmt.Sprintf("\n Workers: %d\n PollInterval: %s\n Timeout: %s\n MaxAttempts: %d\n BackoffBase: %s\n BackoffMax: %s",
+		c.Workers,
+		c.PollInterval,
+		c.Timeout,
+		c.MaxAttempts,
+		c.BackoffBase,
+		c.BackoffMax,
+	)
+}
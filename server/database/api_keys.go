@@ -0,0 +1,114 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type APIKeyScope string
+
+const (
+	APIKeyScopeEventsRead   APIKeyScope = "events:read"
+	APIKeyScopeEventsImport APIKeyScope = "events:import"
+	APIKeyScopeClubsRead    APIKeyScope = "clubs:read"
+)
+
+var AllAPIKeyScopes = []APIKeyScope{
+	APIKeyScopeEventsRead,
+	APIKeyScopeEventsImport,
+	APIKeyScopeClubsRead,
+}
+
+type APIKey struct {
+	ID         int            `db:"api_key_id"`
+	UserID     string         `db:"api_key_user_id"`
+	Name       string         `db:"api_key_name"`
+	Prefix     string         `db:"api_key_prefix"`
+	Hash       string         `db:"api_key_hash"`
+	Scopes     pq.StringArray `db:"api_key_scopes"`
+	ClubIDs    pq.StringArray `db:"api_key_club_ids"`
+	RateLimit  int            `db:"api_key_rate_limit"`
+	CreatedAt  time.Time      `db:"api_key_created_at"`
+	LastUsedAt *time.Time     `db:"api_key_last_used_at"`
+}
+
+func (d *Database) InsertAPIKey(ctx context.Context, key APIKey) (int, error) {
+	query := `
+		INSERT INTO api_keys (api_key_user_id, api_key_name, api_key_prefix, api_key_hash, api_key_scopes, api_key_club_ids, api_key_rate_limit)
+		VALUES (:api_key_user_id, :api_key_name, :api_key_prefix, :api_key_hash, :api_key_scopes, :api_key_club_ids, :api_key_rate_limit)
+		RETURNING api_key_id
+	`
+
+	query, args, err := d.db.BindNamed(query, key)
+	if err != nil {
+		return 0, fmt.Errorf("failed to bind named query: %w", err)
+	}
+
+	var id int
+	if err = d.db.GetContext(ctx, &id, query, args...); err != nil {
+		return 0, fmt.Errorf("failed to insert api key: %w", err)
+	}
+
+	return id, nil
+}
+
+func (d *Database) GetAPIKeys(ctx context.Context, userID string) ([]APIKey, error) {
+	query := `
+		SELECT *
+		FROM api_keys
+		WHERE api_key_user_id = $1
+		ORDER BY api_key_created_at DESC
+	`
+
+	var keys []APIKey
+	if err := d.db.SelectContext(ctx, &keys, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to get api keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+func (d *Database) GetAPIKeyByHash(ctx context.Context, hash string) (*APIKey, error) {
+	query := `
+		SELECT *
+		FROM api_keys
+		WHERE api_key_hash = $1
+	`
+
+	var key APIKey
+	if err := d.db.GetContext(ctx, &key, query, hash); err != nil {
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	return &key, nil
+}
+
+func (d *Database) UpdateAPIKeyLastUsed(ctx context.Context, id int) error {
+	query := `
+		UPDATE api_keys
+		SET api_key_last_used_at = now()
+		WHERE api_key_id = $1
+	`
+
+	if _, err := d.db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to update api key last used: %w", err)
+	}
+
+	return nil
+}
+
+func (d *Database) DeleteAPIKey(ctx context.Context, id int, userID string) error {
+	query := `
+		DELETE FROM api_keys
+		WHERE api_key_id = $1 AND api_key_user_id = $2
+	`
+
+	if _, err := d.db.ExecContext(ctx, query, id, userID); err != nil {
+		return fmt.Errorf("failed to delete api key: %w", err)
+	}
+
+	return nil
+}
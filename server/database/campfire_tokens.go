@@ -31,17 +31,16 @@ func (d *Database) GetCampfireTokens(ctx context.Context) ([]CampfireToken, erro
 	return tokens, nil
 }
 
-func (d *Database) GetNextCampfireToken(ctx context.Context) (*CampfireToken, error) {
-	query := `SELECT * FROM campfire_tokens WHERE campfire_token_expires_at > $1 ORDER BY campfire_token_expires_at LIMIT 1`
+func (d *Database) GetValidCampfireTokens(ctx context.Context) ([]CampfireToken, error) {
+	query := `SELECT * FROM campfire_tokens WHERE campfire_token_expires_at > $1 ORDER BY campfire_token_expires_at`
 
 	now := time.Now().Add(time.Minute)
 
-	var campfireToken CampfireToken
-	if err := d.db.GetContext(ctx, &campfireToken, query, now); err != nil {
+	var tokens []CampfireToken
+	if err := d.db.SelectContext(ctx, &tokens, query, now); err != nil {
 		return nil, err
 	}
-
-	return &campfireToken, nil
+	return tokens, nil
 }
 
 func (d *Database) DeleteExpiredCampfireTokens(ctx context.Context) (int, error) {
@@ -3,6 +3,9 @@ package database
 import (
 	"context"
 	"fmt"
+	"time"
+
+	"github.com/lib/pq"
 )
 
 func (d *Database) GetClubs(ctx context.Context, order string) ([]ClubWithEvents, error) {
@@ -105,6 +108,158 @@ func (d *Database) UpdateClub(ctx context.Context, clubID string, autoImport boo
 	return nil
 }
 
+func (d *Database) UpdateClubRaffleExclusions(ctx context.Context, clubID string, blockedMembers []string, cooldownDays int, excludeCommunityAmbassadors bool) error {
+	query := `
+		UPDATE clubs
+		SET club_raffle_blocked_members = $1,
+			club_raffle_cooldown_days = $2,
+			club_raffle_exclude_community_ambassadors = $3
+		WHERE club_id = $4
+	`
+
+	if _, err := d.db.ExecContext(ctx, query, pq.Array(blockedMembers), cooldownDays, excludeCommunityAmbassadors, clubID); err != nil {
+		return fmt.Errorf("failed to update club raffle exclusions: %w", err)
+	}
+
+	return nil
+}
+
+func (d *Database) UpdateClubDigest(ctx context.Context, clubID string, webhookURL *string, weekly bool, quarterly bool, weekday time.Weekday, hour int) error {
+	// reset the last digest times when a digest gets enabled, so it is first sent at the next scheduled time instead of immediately
+	query := `
+		UPDATE clubs
+		SET club_digest_webhook_url = $1,
+			club_last_weekly_digest_at = CASE WHEN NOT club_digest_weekly AND $2 THEN now() ELSE club_last_weekly_digest_at END,
+			club_last_quarterly_digest_at = CASE WHEN NOT club_digest_quarterly AND $3 THEN now() ELSE club_last_quarterly_digest_at END,
+			club_digest_weekly = $2,
+			club_digest_quarterly = $3,
+			club_digest_weekday = $4,
+			club_digest_hour = $5
+		WHERE club_id = $6
+	`
+
+	if _, err := d.db.ExecContext(ctx, query, webhookURL, weekly, quarterly, int(weekday), hour, clubID); err != nil {
+		return fmt.Errorf("failed to update club digest: %w", err)
+	}
+
+	return nil
+}
+
+func (d *Database) UpdateClubLeaderboardOptOut(ctx context.Context, clubID string, optOut bool) error {
+	query := `
+		UPDATE clubs
+		SET club_leaderboard_opt_out = $1
+		WHERE club_id = $2
+	`
+
+	if _, err := d.db.ExecContext(ctx, query, optOut, clubID); err != nil {
+		return fmt.Errorf("failed to update club leaderboard opt out: %w", err)
+	}
+
+	return nil
+}
+
+func (d *Database) UpdateClubCalendarToken(ctx context.Context, clubID string, token *string) error {
+	query := `
+		UPDATE clubs
+		SET club_calendar_token = $1
+		WHERE club_id = $2
+	`
+
+	if _, err := d.db.ExecContext(ctx, query, token, clubID); err != nil {
+		return fmt.Errorf("failed to update club calendar token: %w", err)
+	}
+
+	return nil
+}
+
+func (d *Database) GetClubsWithDigests(ctx context.Context) ([]Club, error) {
+	query := `
+		SELECT *
+		FROM clubs
+		WHERE club_digest_webhook_url IS NOT NULL AND (club_digest_weekly = TRUE OR club_digest_quarterly = TRUE)
+	`
+
+	var clubs []Club
+	if err := d.db.SelectContext(ctx, &clubs, query); err != nil {
+		return nil, fmt.Errorf("failed to get clubs with digests: %w", err)
+	}
+
+	return clubs, nil
+}
+
+// ClaimClubWeeklyDigest records the weekly digest of the club as sent, unless it was already sent at or after the given scheduled time.
+// It reports whether the digest was claimed, so only one job posts the digest of a week.
+func (d *Database) ClaimClubWeeklyDigest(ctx context.Context, clubID string, scheduledAt time.Time) (bool, error) {
+	query := `
+		UPDATE clubs
+		SET club_last_weekly_digest_at = now()
+		WHERE club_id = $1 AND club_last_weekly_digest_at < $2
+	`
+
+	res, err := d.db.ExecContext(ctx, query, clubID, scheduledAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim club weekly digest: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim club weekly digest: %w", err)
+	}
+
+	return rows == 1, nil
+}
+
+func (d *Database) UpdateClubLastWeeklyDigest(ctx context.Context, clubID string, at time.Time) error {
+	query := `
+		UPDATE clubs
+		SET club_last_weekly_digest_at = $1
+		WHERE club_id = $2
+	`
+
+	if _, err := d.db.ExecContext(ctx, query, at, clubID); err != nil {
+		return fmt.Errorf("failed to update club last weekly digest: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimClubQuarterlyDigest records the quarterly digest of the club as sent, unless it was already sent at or after the given scheduled time.
+// It reports whether the digest was claimed, so only one job posts the digest of a quarter.
+func (d *Database) ClaimClubQuarterlyDigest(ctx context.Context, clubID string, scheduledAt time.Time) (bool, error) {
+	query := `
+		UPDATE clubs
+		SET club_last_quarterly_digest_at = now()
+		WHERE club_id = $1 AND club_last_quarterly_digest_at < $2
+	`
+
+	res, err := d.db.ExecContext(ctx, query, clubID, scheduledAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim club quarterly digest: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim club quarterly digest: %w", err)
+	}
+
+	return rows == 1, nil
+}
+
+func (d *Database) UpdateClubLastQuarterlyDigest(ctx context.Context, clubID string, at time.Time) error {
+	query := `
+		UPDATE clubs
+		SET club_last_quarterly_digest_at = $1
+		WHERE club_id = $2
+	`
+
+	if _, err := d.db.ExecContext(ctx, query, at, clubID); err != nil {
+		return fmt.Errorf("failed to update club last quarterly digest: %w", err)
+	}
+
+	return nil
+}
+
 func (d *Database) UpdateClubLastAutoEventImported(ctx context.Context, clubID string) error {
 	query := `
 		UPDATE clubs
@@ -119,21 +274,20 @@ func (d *Database) UpdateClubLastAutoEventImported(ctx context.Context, clubID s
 	return nil
 }
 
-func (d *Database) GetNextClubImport(ctx context.Context) (*Club, error) {
+func (d *Database) GetClubsDueForEventImport(ctx context.Context) ([]Club, error) {
 	query := `
 		SELECT *
 		FROM clubs
 		WHERE club_auto_event_import = TRUE AND (club_last_auto_event_imported_at < now() - INTERVAL '1 hour')
 		ORDER BY club_last_auto_event_imported_at
-		LIMIT 1
 	`
 
-	var club Club
-	if err := d.db.GetContext(ctx, &club, query); err != nil {
-		return nil, fmt.Errorf("failed to get next club to import: %w", err)
+	var clubs []Club
+	if err := d.db.SelectContext(ctx, &clubs, query); err != nil {
+		return nil, fmt.Errorf("failed to get clubs to import: %w", err)
 	}
 
-	return &club, nil
+	return clubs, nil
 }
 
 func (d *Database) GetClubEventCreators(ctx context.Context, clubID string) ([]Member, error) {
@@ -21,18 +21,32 @@ func (d *Database) GetClubImportJobs(ctx context.Context) ([]ClubImportJobWithCl
 	return jobs, nil
 }
 
-func (d *Database) GetNextPendingClubImportJob(ctx context.Context) (*ClubImportJob, error) {
+func (d *Database) GetPendingClubImportJobs(ctx context.Context) ([]ClubImportJob, error) {
 	query := `
 		SELECT *
 		FROM club_import_jobs
 		WHERE club_import_job_status = 'pending'
-		ORDER BY club_import_job_last_tried_at , club_import_job_created_at
-		LIMIT 1
+		ORDER BY club_import_job_last_tried_at, club_import_job_created_at
+	`
+
+	var jobs []ClubImportJob
+	if err := d.db.SelectContext(ctx, &jobs, query); err != nil {
+		return nil, fmt.Errorf("failed to get pending club import jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+func (d *Database) GetClubImportJob(ctx context.Context, jobID int) (*ClubImportJob, error) {
+	query := `
+		SELECT *
+		FROM club_import_jobs
+		WHERE club_import_job_id = $1
 	`
 
 	var job ClubImportJob
-	if err := d.db.GetContext(ctx, &job, query); err != nil {
-		return nil, fmt.Errorf("failed to get next pending club import job: %w", err)
+	if err := d.db.GetContext(ctx, &job, query, jobID); err != nil {
+		return nil, fmt.Errorf("failed to get club import job: %w", err)
 	}
 
 	return &job, nil
@@ -0,0 +1,257 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/topi314/campfire-tools/internal/xpgtype"
+)
+
+// EventCategory groups live events by their name. An event belongs to the first category by position with a pattern contained in its live event name.
+type EventCategory struct {
+	ID        int            `db:"event_category_id"`
+	Name      string         `db:"event_category_name"`
+	Patterns  pq.StringArray `db:"event_category_patterns"`
+	Position  int            `db:"event_category_position"`
+	CreatedAt time.Time      `db:"event_category_created_at"`
+}
+
+// DigitalCodeExcludePattern is an ILIKE pattern of live event names whose check-ins do not count towards digital codes.
+type DigitalCodeExcludePattern struct {
+	ID        int       `db:"digital_code_exclude_pattern_id"`
+	Pattern   string    `db:"digital_code_exclude_pattern_pattern"`
+	CreatedAt time.Time `db:"digital_code_exclude_pattern_created_at"`
+}
+
+// ConfiguredEvent is an event selectable on the event stats page, made up of one live event per day.
+type ConfiguredEvent struct {
+	ID        int                                `db:"configured_event_id"`
+	Key       string                             `db:"configured_event_key"`
+	Name      string                             `db:"configured_event_name"`
+	Days      xpgtype.JSON[[]ConfiguredEventDay] `db:"configured_event_days"`
+	Position  int                                `db:"configured_event_position"`
+	CreatedAt time.Time                          `db:"configured_event_created_at"`
+}
+
+type ConfiguredEventDay struct {
+	Label       string `json:"label"`
+	LiveEventID string `json:"live_event_id"`
+}
+
+// LiveEvent is a Campfire live event seen in the imported events.
+type LiveEvent struct {
+	ID          string    `db:"live_event_id"`
+	Name        string    `db:"live_event_name"`
+	Events      int       `db:"live_event_events"`
+	LastEventAt time.Time `db:"live_event_last_event_at"`
+}
+
+func (d *Database) GetEventCategories(ctx context.Context) ([]EventCategory, error) {
+	query := `
+		SELECT *
+		FROM event_categories
+		ORDER BY event_category_position, event_category_id
+	`
+
+	var categories []EventCategory
+	if err := d.db.SelectContext(ctx, &categories, query); err != nil {
+		return nil, fmt.Errorf("failed to get event categories: %w", err)
+	}
+
+	return categories, nil
+}
+
+func (d *Database) InsertEventCategory(ctx context.Context, category EventCategory) (int, error) {
+	query := `
+		INSERT INTO event_categories (event_category_name, event_category_patterns, event_category_position)
+		VALUES (:event_category_name, :event_category_patterns, :event_category_position)
+		RETURNING event_category_id
+	`
+
+	query, args, err := d.db.BindNamed(query, category)
+	if err != nil {
+		return 0, fmt.Errorf("failed to bind named query: %w", err)
+	}
+
+	var id int
+	if err = d.db.GetContext(ctx, &id, query, args...); err != nil {
+		return 0, fmt.Errorf("failed to insert event category: %w", err)
+	}
+
+	return id, nil
+}
+
+func (d *Database) UpdateEventCategory(ctx context.Context, category EventCategory) error {
+	query := `
+		UPDATE event_categories
+		SET event_category_name = :event_category_name,
+			event_category_patterns = :event_category_patterns,
+			event_category_position = :event_category_position
+		WHERE event_category_id = :event_category_id
+	`
+
+	if _, err := d.db.NamedExecContext(ctx, query, category); err != nil {
+		return fmt.Errorf("failed to update event category: %w", err)
+	}
+
+	return nil
+}
+
+// AddEventCategoryPattern adds the pattern to the category, unless the category already has it.
+func (d *Database) AddEventCategoryPattern(ctx context.Context, id int, pattern string) error {
+	query := `
+		UPDATE event_categories
+		SET event_category_patterns = array_append(event_category_patterns, $2)
+		WHERE event_category_id = $1 AND NOT ($2 = ANY(event_category_patterns))
+	`
+
+	if _, err := d.db.ExecContext(ctx, query, id, pattern); err != nil {
+		return fmt.Errorf("failed to add event category pattern: %w", err)
+	}
+
+	return nil
+}
+
+func (d *Database) DeleteEventCategory(ctx context.Context, id int) error {
+	query := `
+		DELETE FROM event_categories
+		WHERE event_category_id = $1
+	`
+
+	if _, err := d.db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to delete event category: %w", err)
+	}
+
+	return nil
+}
+
+func (d *Database) GetDigitalCodeExcludePatterns(ctx context.Context) ([]DigitalCodeExcludePattern, error) {
+	query := `
+		SELECT *
+		FROM digital_code_exclude_patterns
+		ORDER BY digital_code_exclude_pattern_pattern
+	`
+
+	var patterns []DigitalCodeExcludePattern
+	if err := d.db.SelectContext(ctx, &patterns, query); err != nil {
+		return nil, fmt.Errorf("failed to get digital code exclude patterns: %w", err)
+	}
+
+	return patterns, nil
+}
+
+func (d *Database) InsertDigitalCodeExcludePattern(ctx context.Context, pattern string) error {
+	query := `
+		INSERT INTO digital_code_exclude_patterns (digital_code_exclude_pattern_pattern)
+		VALUES ($1)
+		ON CONFLICT (digital_code_exclude_pattern_pattern) DO NOTHING
+	`
+
+	if _, err := d.db.ExecContext(ctx, query, pattern); err != nil {
+		return fmt.Errorf("failed to insert digital code exclude pattern: %w", err)
+	}
+
+	return nil
+}
+
+func (d *Database) DeleteDigitalCodeExcludePattern(ctx context.Context, id int) error {
+	query := `
+		DELETE FROM digital_code_exclude_patterns
+		WHERE digital_code_exclude_pattern_id = $1
+	`
+
+	if _, err := d.db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to delete digital code exclude pattern: %w", err)
+	}
+
+	return nil
+}
+
+func (d *Database) GetConfiguredEvents(ctx context.Context) ([]ConfiguredEvent, error) {
+	query := `
+		SELECT *
+		FROM configured_events
+		ORDER BY configured_event_position, configured_event_id
+	`
+
+	var events []ConfiguredEvent
+	if err := d.db.SelectContext(ctx, &events, query); err != nil {
+		return nil, fmt.Errorf("failed to get configured events: %w", err)
+	}
+
+	return events, nil
+}
+
+func (d *Database) InsertConfiguredEvent(ctx context.Context, event ConfiguredEvent) (int, error) {
+	query := `
+		INSERT INTO configured_events (configured_event_key, configured_event_name, configured_event_days, configured_event_position)
+		VALUES (:configured_event_key, :configured_event_name, :configured_event_days, :configured_event_position)
+		RETURNING configured_event_id
+	`
+
+	query, args, err := d.db.BindNamed(query, event)
+	if err != nil {
+		return 0, fmt.Errorf("failed to bind named query: %w", err)
+	}
+
+	var id int
+	if err = d.db.GetContext(ctx, &id, query, args...); err != nil {
+		return 0, fmt.Errorf("failed to insert configured event: %w", err)
+	}
+
+	return id, nil
+}
+
+func (d *Database) UpdateConfiguredEvent(ctx context.Context, event ConfiguredEvent) error {
+	query := `
+		UPDATE configured_events
+		SET configured_event_key = :configured_event_key,
+			configured_event_name = :configured_event_name,
+			configured_event_days = :configured_event_days,
+			configured_event_position = :configured_event_position
+		WHERE configured_event_id = :configured_event_id
+	`
+
+	if _, err := d.db.NamedExecContext(ctx, query, event); err != nil {
+		return fmt.Errorf("failed to update configured event: %w", err)
+	}
+
+	return nil
+}
+
+func (d *Database) DeleteConfiguredEvent(ctx context.Context, id int) error {
+	query := `
+		DELETE FROM configured_events
+		WHERE configured_event_id = $1
+	`
+
+	if _, err := d.db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to delete configured event: %w", err)
+	}
+
+	return nil
+}
+
+// GetLiveEvents returns all live events of the imported events, the most recent first.
+func (d *Database) GetLiveEvents(ctx context.Context) ([]LiveEvent, error) {
+	query := `
+		SELECT event_campfire_live_event_id AS live_event_id,
+			event_campfire_live_event_name AS live_event_name,
+			COUNT(*) AS live_event_events,
+			MAX(event_time) AS live_event_last_event_at
+		FROM events
+		WHERE event_campfire_live_event_id <> ''
+		GROUP BY event_campfire_live_event_id, event_campfire_live_event_name
+		ORDER BY live_event_last_event_at DESC
+	`
+
+	var liveEvents []LiveEvent
+	if err := d.db.SelectContext(ctx, &liveEvents, query); err != nil {
+		return nil, fmt.Errorf("failed to get live events: %w", err)
+	}
+
+	return liveEvents, nil
+}
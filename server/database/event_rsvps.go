@@ -7,16 +7,21 @@ import (
 	"fmt"
 	"log/slog"
 	"slices"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 const batchSize = 10_000
 
+// InsertEventRSVPs inserts or updates RSVPs and records every status change in the event_rsvp_history table.
 func (d *Database) InsertEventRSVPs(ctx context.Context, rsvps []EventRSVP) error {
 	if len(rsvps) == 0 {
 		return nil
 	}
 
-	tx, err := d.db.BeginTx(ctx, nil)
+	tx, err := d.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
 	}
@@ -28,6 +33,11 @@ func (d *Database) InsertEventRSVPs(ctx context.Context, rsvps []EventRSVP) erro
 	}()
 
 	for chunk := range slices.Chunk(rsvps, batchSize) {
+		history, err := eventRSVPTransitions(ctx, tx, chunk)
+		if err != nil {
+			return err
+		}
+
 		query := `
 			INSERT INTO event_rsvps (event_rsvp_event_id, event_rsvp_member_id, event_rsvp_status, event_rsvp_imported_at)
 			VALUES (:event_rsvp_event_id, :event_rsvp_member_id, :event_rsvp_status, now())
@@ -36,10 +46,22 @@ func (d *Database) InsertEventRSVPs(ctx context.Context, rsvps []EventRSVP) erro
 				event_rsvp_imported_at = now()
 			`
 
-		_, err = d.db.NamedExecContext(ctx, query, chunk)
-		if err != nil {
+		if _, err = tx.NamedExecContext(ctx, query, chunk); err != nil {
 			return fmt.Errorf("failed to insert event RSVPs: %w", err)
 		}
+
+		if len(history) == 0 {
+			continue
+		}
+
+		query = `
+			INSERT INTO event_rsvp_history (event_rsvp_history_event_id, event_rsvp_history_member_id, event_rsvp_history_old_status, event_rsvp_history_new_status, event_rsvp_history_observed_at)
+			VALUES (:event_rsvp_history_event_id, :event_rsvp_history_member_id, :event_rsvp_history_old_status, :event_rsvp_history_new_status, now())
+			`
+
+		if _, err = tx.NamedExecContext(ctx, query, history); err != nil {
+			return fmt.Errorf("failed to insert event RSVP history: %w", err)
+		}
 	}
 
 	if err = tx.Commit(); err != nil {
@@ -48,3 +70,132 @@ func (d *Database) InsertEventRSVPs(ctx context.Context, rsvps []EventRSVP) erro
 
 	return nil
 }
+
+// eventRSVPTransitions returns the history entries for all RSVPs which are new or have a different status than the stored one.
+func eventRSVPTransitions(ctx context.Context, tx *sqlx.Tx, rsvps []EventRSVP) ([]EventRSVPHistory, error) {
+	var eventIDs []string
+	for _, rsvp := range rsvps {
+		if !slices.Contains(eventIDs, rsvp.EventID) {
+			eventIDs = append(eventIDs, rsvp.EventID)
+		}
+	}
+
+	query := `
+		SELECT * FROM event_rsvps
+		WHERE event_rsvp_event_id = ANY($1)
+		FOR UPDATE
+	`
+
+	var current []EventRSVP
+	if err := tx.SelectContext(ctx, &current, query, pq.Array(eventIDs)); err != nil {
+		return nil, fmt.Errorf("failed to get current event RSVPs: %w", err)
+	}
+
+	statuses := make(map[[2]string]string, len(current))
+	for _, rsvp := range current {
+		statuses[[2]string{rsvp.EventID, rsvp.MemberID}] = rsvp.Status
+	}
+
+	var history []EventRSVPHistory
+	for _, rsvp := range rsvps {
+		key := [2]string{rsvp.EventID, rsvp.MemberID}
+		oldStatus, ok := statuses[key]
+		if ok && oldStatus == rsvp.Status {
+			continue
+		}
+
+		entry := EventRSVPHistory{
+			EventID:   rsvp.EventID,
+			MemberID:  rsvp.MemberID,
+			NewStatus: rsvp.Status,
+		}
+		if ok {
+			entry.OldStatus = &oldStatus
+		}
+		history = append(history, entry)
+
+		// the same RSVP could be in the batch twice, so the second one should transition from the first one
+		statuses[key] = rsvp.Status
+	}
+
+	return history, nil
+}
+
+// GetEventRSVPs returns the stored RSVPs of an event.
+func (d *Database) GetEventRSVPs(ctx context.Context, eventID string) ([]EventRSVP, error) {
+	query := `
+		SELECT *
+		FROM event_rsvps
+		WHERE event_rsvp_event_id = $1
+	`
+
+	var rsvps []EventRSVP
+	if err := d.db.SelectContext(ctx, &rsvps, query, eventID); err != nil {
+		return nil, fmt.Errorf("failed to get event RSVPs: %w", err)
+	}
+
+	return rsvps, nil
+}
+
+func (d *Database) GetEventRSVPHistory(ctx context.Context, eventID string) ([]EventRSVPHistoryWithMember, error) {
+	query := `
+		SELECT event_rsvp_history.*, members.*
+		FROM event_rsvp_history
+		JOIN members ON event_rsvp_history_member_id = member_id
+		WHERE event_rsvp_history_event_id = $1
+		ORDER BY event_rsvp_history_observed_at, event_rsvp_history_id
+	`
+
+	var history []EventRSVPHistoryWithMember
+	if err := d.db.SelectContext(ctx, &history, query, eventID); err != nil {
+		return nil, fmt.Errorf("failed to get event RSVP history: %w", err)
+	}
+
+	return history, nil
+}
+
+// StreamEventRSVPExports calls fn for every RSVP of the club's events matching the filters, ordered by event time and grouped by event.
+// Rows are read one at a time, so a whole club can be exported without loading it into memory.
+func (d *Database) StreamEventRSVPExports(ctx context.Context, clubID string, from time.Time, to time.Time, caOnly bool, eventCreator string, fn func(rsvp EventRSVPExport) error) error {
+	query := `
+		SELECT event_id, event_name, event_details, event_address, event_location, event_creator_id, event_cover_photo_url,
+			event_time, event_end_time, event_finished, event_discord_interested, event_created_by_community_ambassador,
+			event_campfire_live_event_id, event_campfire_live_event_name, event_club_id,
+			event_rsvps.*,
+			members.member_id, members.member_username, members.member_display_name, members.member_avatar_url,
+			creator.member_username AS creator_username,
+			creator.member_display_name AS creator_display_name
+		FROM events
+		JOIN event_rsvps ON event_id = event_rsvp_event_id
+		JOIN members ON event_rsvp_member_id = members.member_id
+		JOIN members creator ON event_creator_id = creator.member_id
+		WHERE event_club_id = $1
+		AND ($2 = '0001-01-01 00:00:00'::timestamp OR event_time >= $2)
+		AND ($3 = '0001-01-01 00:00:00'::timestamp OR event_time <= $3)
+		AND (NOT $4 OR event_created_by_community_ambassador = TRUE)
+		AND ($5 = '' OR event_creator_id = $5)
+		ORDER BY event_time, event_id, members.member_username, members.member_id
+	`
+
+	rows, err := d.db.QueryxContext(ctx, query, clubID, from, to, caOnly, eventCreator)
+	if err != nil {
+		return fmt.Errorf("failed to query event RSVP exports: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rsvp EventRSVPExport
+		if err = rows.StructScan(&rsvp); err != nil {
+			return fmt.Errorf("failed to scan event RSVP export: %w", err)
+		}
+		if err = fn(rsvp); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("failed to read event RSVP exports: %w", err)
+	}
+
+	return nil
+}
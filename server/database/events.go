@@ -281,21 +281,20 @@ func (d *Database) UpdateEventLastAutoImported(ctx context.Context, eventID stri
 	return nil
 }
 
-func (d *Database) GetNextUpdateEvent(ctx context.Context) (*Event, error) {
-	// get the first event which has not finished yet and the club is set to auto import events
+// GetEventsDueForUpdate returns all events which have not finished yet, belong to a club with auto import enabled and were not updated in the last hour.
+func (d *Database) GetEventsDueForUpdate(ctx context.Context) ([]Event, error) {
 	query := `
 			SELECT events.*
 			FROM events
 			JOIN clubs ON event_club_id = club_id
 			WHERE event_finished = FALSE AND club_auto_event_import = TRUE AND event_last_auto_imported_at < now() - INTERVAL '1 hour'
 			ORDER BY event_last_auto_imported_at, event_end_time
-			LIMIT 1
 		`
 
-	var event Event
-	if err := d.db.GetContext(ctx, &event, query); err != nil {
-		return nil, fmt.Errorf("failed to get next event to update: %w", err)
+	var events []Event
+	if err := d.db.SelectContext(ctx, &events, query); err != nil {
+		return nil, fmt.Errorf("failed to get events to update: %w", err)
 	}
 
-	return &event, nil
+	return events, nil
 }
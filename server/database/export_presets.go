@@ -0,0 +1,105 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ExportPreset is a saved set of export options of a user for a club.
+// With an EventRange the events are selected when the preset is run, otherwise they are picked in the export form.
+type ExportPreset struct {
+	ID                    int            `db:"export_preset_id"`
+	UserID                string         `db:"export_preset_user_id"`
+	ClubID                string         `db:"export_preset_club_id"`
+	Name                  string         `db:"export_preset_name"`
+	IncludedFields        pq.StringArray `db:"export_preset_included_fields"`
+	Format                string         `db:"export_preset_format"`
+	CombineCSV            bool           `db:"export_preset_combine_csv"`
+	IncludeMissingMembers bool           `db:"export_preset_include_missing_members"`
+	EventRange            string         `db:"export_preset_event_range"`
+	OnlyCAEvents          bool           `db:"export_preset_only_ca_events"`
+	EventCreator          string         `db:"export_preset_event_creator"`
+	Categories            pq.StringArray `db:"export_preset_categories"`
+	CreatedAt             time.Time      `db:"export_preset_created_at"`
+}
+
+// UpsertExportPreset saves the preset, replacing an existing preset of the user for the club with the same name.
+func (d *Database) UpsertExportPreset(ctx context.Context, preset ExportPreset) (int, error) {
+	query := `
+		INSERT INTO export_presets (export_preset_user_id, export_preset_club_id, export_preset_name, export_preset_included_fields, export_preset_format,
+			export_preset_combine_csv, export_preset_include_missing_members, export_preset_event_range, export_preset_only_ca_events,
+			export_preset_event_creator, export_preset_categories)
+		VALUES (:export_preset_user_id, :export_preset_club_id, :export_preset_name, :export_preset_included_fields, :export_preset_format,
+			:export_preset_combine_csv, :export_preset_include_missing_members, :export_preset_event_range, :export_preset_only_ca_events,
+			:export_preset_event_creator, :export_preset_categories)
+		ON CONFLICT (export_preset_user_id, export_preset_club_id, export_preset_name) DO UPDATE SET
+			export_preset_included_fields = EXCLUDED.export_preset_included_fields,
+			export_preset_format = EXCLUDED.export_preset_format,
+			export_preset_combine_csv = EXCLUDED.export_preset_combine_csv,
+			export_preset_include_missing_members = EXCLUDED.export_preset_include_missing_members,
+			export_preset_event_range = EXCLUDED.export_preset_event_range,
+			export_preset_only_ca_events = EXCLUDED.export_preset_only_ca_events,
+			export_preset_event_creator = EXCLUDED.export_preset_event_creator,
+			export_preset_categories = EXCLUDED.export_preset_categories
+		RETURNING export_preset_id
+	`
+
+	query, args, err := d.db.BindNamed(query, preset)
+	if err != nil {
+		return 0, fmt.Errorf("failed to bind named query: %w", err)
+	}
+
+	var id int
+	if err = d.db.GetContext(ctx, &id, query, args...); err != nil {
+		return 0, fmt.Errorf("failed to upsert export preset: %w", err)
+	}
+
+	return id, nil
+}
+
+func (d *Database) GetExportPresets(ctx context.Context, userID string, clubID string) ([]ExportPreset, error) {
+	query := `
+		SELECT *
+		FROM export_presets
+		WHERE export_preset_user_id = $1 AND export_preset_club_id = $2
+		ORDER BY export_preset_name
+	`
+
+	var presets []ExportPreset
+	if err := d.db.SelectContext(ctx, &presets, query, userID, clubID); err != nil {
+		return nil, fmt.Errorf("failed to get export presets: %w", err)
+	}
+
+	return presets, nil
+}
+
+func (d *Database) GetExportPreset(ctx context.Context, id int, userID string, clubID string) (*ExportPreset, error) {
+	query := `
+		SELECT *
+		FROM export_presets
+		WHERE export_preset_id = $1 AND export_preset_user_id = $2 AND export_preset_club_id = $3
+	`
+
+	var preset ExportPreset
+	if err := d.db.GetContext(ctx, &preset, query, id, userID, clubID); err != nil {
+		return nil, fmt.Errorf("failed to get export preset: %w", err)
+	}
+
+	return &preset, nil
+}
+
+func (d *Database) DeleteExportPreset(ctx context.Context, id int, userID string, clubID string) error {
+	query := `
+		DELETE FROM export_presets
+		WHERE export_preset_id = $1 AND export_preset_user_id = $2 AND export_preset_club_id = $3
+	`
+
+	if _, err := d.db.ExecContext(ctx, query, id, userID, clubID); err != nil {
+		return fmt.Errorf("failed to delete export preset: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// HostStats is a member who created events of a club with the totals of these events.
+type HostStats struct {
+	Member
+	Events   int `db:"events"`
+	CAEvents int `db:"ca_events"`
+	Accepted int `db:"accepted"`
+	CheckIns int `db:"check_ins"`
+}
+
+// GetClubHostStats returns every event creator of the club with the number of their events, CA events, accepted members and check-ins, the most check-ins first.
+func (d *Database) GetClubHostStats(ctx context.Context, clubID string, from time.Time, to time.Time, caOnly bool, eventCreator string) ([]HostStats, error) {
+	query := `
+		SELECT m.*,
+			COUNT(*) AS events,
+			COUNT(*) FILTER (WHERE e.event_created_by_community_ambassador = TRUE) AS ca_events,
+			COALESCE(SUM(r.accepted), 0)::INT AS accepted,
+			COALESCE(SUM(r.check_ins), 0)::INT AS check_ins
+		FROM events e
+		JOIN members m ON e.event_creator_id = m.member_id
+		LEFT JOIN LATERAL (
+			SELECT
+				COUNT(*) AS accepted,
+				COUNT(*) FILTER (WHERE event_rsvp_status = 'CHECKED_IN') AS check_ins
+			FROM event_rsvps
+			WHERE event_rsvp_event_id = e.event_id
+			AND event_rsvp_status IN ('ACCEPTED', 'CHECKED_IN')
+		) r ON true
+		WHERE e.event_club_id = $1
+		AND ($2 = '0001-01-01 00:00:00'::timestamp OR e.event_time >= $2)
+		AND ($3 = '0001-01-01 00:00:00'::timestamp OR e.event_time <= $3)
+		AND (NOT $4 OR e.event_created_by_community_ambassador = TRUE)
+		AND ($5 = '' OR e.event_creator_id = $5)
+		GROUP BY m.member_id
+		ORDER BY check_ins DESC, events DESC, m.member_username
+	`
+
+	var hosts []HostStats
+	if err := d.db.SelectContext(ctx, &hosts, query, clubID, from, to, caOnly, eventCreator); err != nil {
+		return nil, fmt.Errorf("failed to get club host stats: %w", err)
+	}
+
+	return hosts, nil
+}
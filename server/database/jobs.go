@@ -0,0 +1,268 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// ErrJobRunning is returned when a job can not be retried because the same work is currently running.
+var ErrJobRunning = errors.New("a job with the same kind and key is currently running")
+
+// InsertJobs queues the given jobs. Jobs which already have a queued, running or cancelled job with the same kind and key are skipped.
+func (d *Database) InsertJobs(ctx context.Context, jobs []Job) error {
+	return insertJobs(ctx, d.db, jobs)
+}
+
+func insertJobs(ctx context.Context, e sqlx.ExtContext, jobs []Job) error {
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO jobs (job_kind, job_key, job_priority, job_status, job_max_attempts, job_next_run_at)
+		VALUES (:job_kind, :job_key, :job_priority, 'queued', :job_max_attempts, now())
+		ON CONFLICT (job_kind, job_key) WHERE job_status IN ('queued', 'running', 'cancelled') DO NOTHING
+	`
+
+	if _, err := sqlx.NamedExecContext(ctx, e, query, jobs); err != nil {
+		return fmt.Errorf("failed to insert jobs: %w", err)
+	}
+	return nil
+}
+
+// ClaimNextJob marks the next due job as running and returns it. It returns sql.ErrNoRows if no job is due.
+func (d *Database) ClaimNextJob(ctx context.Context) (*Job, error) {
+	query := `
+		UPDATE jobs
+		SET job_status = 'running',
+			job_attempts = job_attempts + 1,
+			job_updated_at = now()
+		WHERE job_id = (
+			SELECT job_id
+			FROM jobs
+			WHERE job_status = 'queued' AND job_next_run_at <= now()
+			ORDER BY job_priority DESC, job_next_run_at, job_id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`
+
+	var job Job
+	if err := d.db.GetContext(ctx, &job, query); err != nil {
+		return nil, fmt.Errorf("failed to claim next job: %w", err)
+	}
+
+	return &job, nil
+}
+
+func (d *Database) UpdateJob(ctx context.Context, job Job) error {
+	query := `
+		UPDATE jobs
+		SET job_status = :job_status,
+			job_attempts = :job_attempts,
+			job_next_run_at = :job_next_run_at,
+			job_last_error = :job_last_error,
+			job_updated_at = now()
+		WHERE job_id = :job_id
+	`
+
+	if _, err := d.db.NamedExecContext(ctx, query, job); err != nil {
+		return fmt.Errorf("failed to update job: %w", err)
+	}
+	return nil
+}
+
+func (d *Database) GetJobs(ctx context.Context, statuses []JobStatus, limit int) ([]Job, error) {
+	query := `
+		SELECT *
+		FROM jobs
+		WHERE job_status = ANY($1)
+		ORDER BY job_status = 'running' DESC, job_priority DESC, job_next_run_at, job_id
+		LIMIT CASE WHEN $2 < 0 THEN NULL ELSE $2 END
+	`
+
+	rawStatuses := make([]string, len(statuses))
+	for i, status := range statuses {
+		rawStatuses[i] = string(status)
+	}
+
+	var jobs []Job
+	if err := d.db.SelectContext(ctx, &jobs, query, pq.Array(rawStatuses), limit); err != nil {
+		return nil, fmt.Errorf("failed to get jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// GetJobCounts returns the number of jobs per status.
+func (d *Database) GetJobCounts(ctx context.Context) (map[JobStatus]int, error) {
+	query := `
+		SELECT job_status, COUNT(*)
+		FROM jobs
+		GROUP BY job_status
+	`
+
+	rows, err := d.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[JobStatus]int)
+	for rows.Next() {
+		var (
+			status JobStatus
+			count  int
+		)
+		if err = rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan job count: %w", err)
+		}
+		counts[status] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// RetryJob queues a failed or cancelled job again with a fresh set of attempts.
+// A queued or cancelled job with the same kind and key is deleted, as the retried job replaces it.
+// It returns ErrJobRunning if a job with the same kind and key is currently running.
+func (d *Database) RetryJob(ctx context.Context, jobID int) error {
+	tx, err := d.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			slog.ErrorContext(ctx, "failed to rollback transaction", "error", err)
+		}
+	}()
+
+	query := `
+		SELECT COUNT(*) FILTER (WHERE d.job_status = 'running')
+		FROM jobs j
+		JOIN jobs d ON d.job_kind = j.job_kind AND d.job_key = j.job_key AND d.job_id <> j.job_id
+		WHERE j.job_id = $1
+	`
+
+	var running int
+	if err = tx.GetContext(ctx, &running, query, jobID); err != nil {
+		return fmt.Errorf("failed to get running jobs: %w", err)
+	}
+	if running > 0 {
+		return ErrJobRunning
+	}
+
+	query = `
+		DELETE FROM jobs d
+		USING jobs j
+		WHERE j.job_id = $1
+		AND j.job_status IN ('failed', 'cancelled')
+		AND d.job_kind = j.job_kind
+		AND d.job_key = j.job_key
+		AND d.job_id <> j.job_id
+		AND d.job_status IN ('queued', 'cancelled')
+	`
+
+	if _, err = tx.ExecContext(ctx, query, jobID); err != nil {
+		return fmt.Errorf("failed to delete duplicate jobs: %w", err)
+	}
+
+	query = `
+		UPDATE jobs
+		SET job_status = 'queued',
+			job_attempts = 0,
+			job_next_run_at = now(),
+			job_updated_at = now()
+		WHERE job_id = $1 AND job_status IN ('failed', 'cancelled')
+	`
+
+	if _, err = tx.ExecContext(ctx, query, jobID); err != nil {
+		return fmt.Errorf("failed to retry job: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// CancelJob cancels a queued job. Running jobs can not be cancelled.
+// The same work is not queued again until the cancelled job is deleted by DeleteFinishedJobs or retried.
+func (d *Database) CancelJob(ctx context.Context, jobID int) error {
+	// the pending run of a cancelled scheduled export job is marked as failed, as it is never run
+	query := `
+		WITH cancelled AS (
+			UPDATE jobs
+			SET job_status = 'cancelled',
+				job_updated_at = now()
+			WHERE job_id = $1 AND job_status = 'queued'
+			RETURNING job_kind, job_key
+		)
+		UPDATE scheduled_export_runs
+		SET scheduled_export_run_status = $3,
+			scheduled_export_run_error = 'The job of the run was cancelled'
+		FROM cancelled
+		WHERE cancelled.job_kind = $2
+		AND scheduled_export_run_id::TEXT = cancelled.job_key
+		AND scheduled_export_run_status = $4
+	`
+
+	if _, err := d.db.ExecContext(ctx, query, jobID, JobKindRunScheduledExport, ScheduledExportRunStatusFailed, ScheduledExportRunStatusPending); err != nil {
+		return fmt.Errorf("failed to cancel job: %w", err)
+	}
+	return nil
+}
+
+// RequeueRunningJobs puts jobs which were running when the server stopped back into the queue.
+func (d *Database) RequeueRunningJobs(ctx context.Context) (int, error) {
+	query := `
+		UPDATE jobs
+		SET job_status = 'queued',
+			job_next_run_at = now(),
+			job_updated_at = now()
+		WHERE job_status = 'running'
+	`
+
+	res, err := d.db.ExecContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue running jobs: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	return int(rows), err
+}
+
+// DeleteFailedJobs deletes failed jobs which were last updated before the given time.
+func (d *Database) DeleteFailedJobs(ctx context.Context, before time.Time) error {
+	query := `
+		DELETE FROM jobs
+		WHERE job_status = 'failed' AND job_updated_at < $1
+	`
+
+	if _, err := d.db.ExecContext(ctx, query, before); err != nil {
+		return fmt.Errorf("failed to delete failed jobs: %w", err)
+	}
+	return nil
+}
+
+// DeleteFinishedJobs deletes completed and cancelled jobs which were last updated before the given time.
+func (d *Database) DeleteFinishedJobs(ctx context.Context, before time.Time) error {
+	query := `
+		DELETE FROM jobs
+		WHERE job_status IN ('completed', 'cancelled') AND job_updated_at < $1
+	`
+
+	if _, err := d.db.ExecContext(ctx, query, before); err != nil {
+		return fmt.Errorf("failed to delete finished jobs: %w", err)
+	}
+	return nil
+}
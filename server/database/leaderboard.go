@@ -0,0 +1,45 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// LeaderboardClub is a club with its accepted members and check-ins of a quarter and its CA check-ins of the quarter before.
+type LeaderboardClub struct {
+	Club
+	Accepted           int `db:"accepted"`
+	CheckIns           int `db:"check_ins"`
+	CACheckIns         int `db:"ca_check_ins"`
+	PreviousCACheckIns int `db:"previous_ca_check_ins"`
+}
+
+// GetClubLeaderboard returns all clubs which did not opt out of the leaderboard and had accepted members between from and to, the most CA check-ins first.
+// The CA check-ins between previousFrom and from are returned as the previous CA check-ins.
+func (d *Database) GetClubLeaderboard(ctx context.Context, from time.Time, to time.Time, previousFrom time.Time) ([]LeaderboardClub, error) {
+	query := `
+		SELECT c.*,
+			COUNT(*) FILTER (WHERE e.event_time >= $1) AS accepted,
+			COUNT(*) FILTER (WHERE e.event_time >= $1 AND er.event_rsvp_status = 'CHECKED_IN') AS check_ins,
+			COUNT(*) FILTER (WHERE e.event_time >= $1 AND er.event_rsvp_status = 'CHECKED_IN' AND e.event_created_by_community_ambassador = TRUE) AS ca_check_ins,
+			COUNT(*) FILTER (WHERE e.event_time < $1 AND er.event_rsvp_status = 'CHECKED_IN' AND e.event_created_by_community_ambassador = TRUE) AS previous_ca_check_ins
+		FROM clubs c
+		JOIN events e ON e.event_club_id = c.club_id
+		JOIN event_rsvps er ON er.event_rsvp_event_id = e.event_id
+		WHERE c.club_leaderboard_opt_out = FALSE
+		AND er.event_rsvp_status IN ('ACCEPTED', 'CHECKED_IN')
+		AND e.event_time >= $3
+		AND e.event_time <= $2
+		GROUP BY c.club_id
+		HAVING COUNT(*) FILTER (WHERE e.event_time >= $1) > 0
+		ORDER BY ca_check_ins DESC, check_ins DESC, c.club_name
+	`
+
+	var clubs []LeaderboardClub
+	if err := d.db.SelectContext(ctx, &clubs, query, from, to, previousFrom); err != nil {
+		return nil, fmt.Errorf("failed to get club leaderboard: %w", err)
+	}
+
+	return clubs, nil
+}
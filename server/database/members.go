@@ -277,3 +277,45 @@ func (d *Database) GetEventCheckInAcceptedCounts(ctx context.Context, clubID str
 
 	return numbers, nil
 }
+
+// NoShowMember is a member with their RSVPs of ended events, how many of them they checked in to and how many they did not.
+type NoShowMember struct {
+	Member
+	RSVPs        int       `db:"rsvps"`
+	CheckIns     int       `db:"check_ins"`
+	NoShows      int       `db:"no_shows"`
+	LastNoShowAt time.Time `db:"last_no_show_at"`
+}
+
+// GetClubNoShowMembers returns the members who accepted ended events of the club without checking in, the most no-shows first.
+// All ended events count, as Campfire does not expose the capacity of an event.
+func (d *Database) GetClubNoShowMembers(ctx context.Context, clubID string, from time.Time, to time.Time, caOnly bool, eventCreator string, limit int) ([]NoShowMember, error) {
+	query := `
+		SELECT m.*,
+			COUNT(*) AS rsvps,
+			COUNT(*) FILTER (WHERE er.event_rsvp_status = 'CHECKED_IN') AS check_ins,
+			COUNT(*) FILTER (WHERE er.event_rsvp_status = 'ACCEPTED') AS no_shows,
+			MAX(e.event_time) FILTER (WHERE er.event_rsvp_status = 'ACCEPTED') AS last_no_show_at
+		FROM event_rsvps er
+		JOIN events e ON er.event_rsvp_event_id = e.event_id
+		JOIN members m ON er.event_rsvp_member_id = m.member_id
+		WHERE e.event_club_id = $1
+		AND er.event_rsvp_status IN ('ACCEPTED', 'CHECKED_IN')
+		AND (e.event_finished = TRUE OR e.event_end_time < now())
+		AND ($2 = '0001-01-01 00:00:00'::timestamp OR e.event_time >= $2)
+		AND ($3 = '0001-01-01 00:00:00'::timestamp OR e.event_time <= $3)
+		AND (NOT $4 OR e.event_created_by_community_ambassador = TRUE)
+		AND ($5 = '' OR e.event_creator_id = $5)
+		GROUP BY m.member_id
+		HAVING COUNT(*) FILTER (WHERE er.event_rsvp_status = 'ACCEPTED') > 0
+		ORDER BY no_shows DESC, check_ins, last_no_show_at DESC, m.member_id
+		LIMIT $6
+	`
+
+	var members []NoShowMember
+	if err := d.db.SelectContext(ctx, &members, query, clubID, from, to, caOnly, eventCreator, limit); err != nil {
+		return nil, fmt.Errorf("failed to get club no-show members: %w", err)
+	}
+
+	return members, nil
+}
@@ -0,0 +1,17 @@
+CREATE TABLE event_rsvp_history
+(
+    event_rsvp_history_id          BIGSERIAL PRIMARY KEY,
+    event_rsvp_history_event_id    VARCHAR   NOT NULL REFERENCES events (event_id) ON DELETE CASCADE,
+    event_rsvp_history_member_id   VARCHAR   NOT NULL REFERENCES members (member_id) ON DELETE CASCADE,
+    event_rsvp_history_old_status  VARCHAR,
+    event_rsvp_history_new_status  VARCHAR   NOT NULL,
+    event_rsvp_history_observed_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX event_rsvp_history_event_id_observed_at_idx
+    ON event_rsvp_history (event_rsvp_history_event_id, event_rsvp_history_observed_at);
+
+-- Seed the history with the current state so every RSVP has a starting point.
+INSERT INTO event_rsvp_history (event_rsvp_history_event_id, event_rsvp_history_member_id, event_rsvp_history_old_status, event_rsvp_history_new_status, event_rsvp_history_observed_at)
+SELECT event_rsvp_event_id, event_rsvp_member_id, NULL, event_rsvp_status, event_rsvp_imported_at
+FROM event_rsvps;
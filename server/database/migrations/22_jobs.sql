@@ -0,0 +1,22 @@
+CREATE TABLE jobs
+(
+    job_id           BIGSERIAL PRIMARY KEY,
+    job_kind         VARCHAR   NOT NULL,
+    job_key          VARCHAR   NOT NULL,
+    job_priority     INTEGER   NOT NULL DEFAULT 0,
+    job_status       VARCHAR   NOT NULL DEFAULT 'queued',
+    job_attempts     INTEGER   NOT NULL DEFAULT 0,
+    job_max_attempts INTEGER   NOT NULL DEFAULT 5,
+    job_next_run_at  TIMESTAMP NOT NULL DEFAULT now(),
+    job_last_error   VARCHAR   NOT NULL DEFAULT '',
+    job_created_at   TIMESTAMP NOT NULL DEFAULT now(),
+    job_updated_at   TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- Only one active job per kind and key, so scheduling the same work twice is a no-op.
+CREATE UNIQUE INDEX jobs_active_kind_key_idx
+    ON jobs (job_kind, job_key)
+    WHERE job_status IN ('queued', 'running');
+
+CREATE INDEX jobs_status_next_run_at_idx
+    ON jobs (job_status, job_priority DESC, job_next_run_at);
@@ -0,0 +1,5 @@
+ALTER TABLE raffles
+    ADD COLUMN raffle_weighting          VARCHAR NOT NULL DEFAULT 'none',
+    ADD COLUMN raffle_bonus_min_check_ins INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN raffle_bonus_tickets      INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN raffle_max_tickets        INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,17 @@
+ALTER TABLE raffles
+    ADD COLUMN raffle_seed            VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN raffle_seed_commitment VARCHAR NOT NULL DEFAULT '';
+
+CREATE TABLE raffle_draws
+(
+    raffle_draw_id              BIGSERIAL PRIMARY KEY,
+    raffle_draw_raffle_id       BIGINT    NOT NULL REFERENCES raffles (raffle_id) ON DELETE CASCADE,
+    raffle_draw_seed_commitment VARCHAR   NOT NULL,
+    raffle_draw_seed            VARCHAR   NOT NULL,
+    raffle_draw_winner_count    INTEGER   NOT NULL,
+    raffle_draw_entrants        JSONB     NOT NULL DEFAULT '[]',
+    raffle_draw_winners         VARCHAR[] NOT NULL DEFAULT '{}',
+    raffle_draw_created_at      TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX raffle_draws_raffle_id_idx ON raffle_draws (raffle_draw_raffle_id);
@@ -0,0 +1,7 @@
+ALTER TABLE clubs
+    ADD COLUMN club_raffle_blocked_members              VARCHAR[] NOT NULL DEFAULT '{}',
+    ADD COLUMN club_raffle_cooldown_days                INTEGER   NOT NULL DEFAULT 0,
+    ADD COLUMN club_raffle_exclude_community_ambassadors BOOLEAN   NOT NULL DEFAULT FALSE;
+
+ALTER TABLE raffles
+    ADD COLUMN raffle_club_id VARCHAR REFERENCES clubs (club_id) ON DELETE SET NULL;
@@ -0,0 +1,14 @@
+CREATE TABLE raffle_prize_tiers
+(
+    raffle_prize_tier_id        BIGSERIAL PRIMARY KEY,
+    raffle_prize_tier_raffle_id BIGINT  NOT NULL REFERENCES raffles (raffle_id) ON DELETE CASCADE,
+    raffle_prize_tier_position  INTEGER NOT NULL,
+    raffle_prize_tier_name      VARCHAR NOT NULL,
+    raffle_prize_tier_count     INTEGER NOT NULL,
+    raffle_prize_tier_reward_id BIGINT REFERENCES rewards (reward_id) ON DELETE SET NULL,
+    UNIQUE (raffle_prize_tier_raffle_id, raffle_prize_tier_position)
+);
+
+ALTER TABLE raffle_winners
+    ADD COLUMN raffle_winner_prize_tier_id  BIGINT REFERENCES raffle_prize_tiers (raffle_prize_tier_id) ON DELETE SET NULL,
+    ADD COLUMN raffle_winner_reward_code_id BIGINT REFERENCES reward_codes (reward_code_id) ON DELETE SET NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE raffles
+    ADD COLUMN raffle_reward_id BIGINT REFERENCES rewards (reward_id) ON DELETE SET NULL;
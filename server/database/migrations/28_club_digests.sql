@@ -0,0 +1,8 @@
+ALTER TABLE clubs
+    ADD COLUMN club_digest_webhook_url        VARCHAR,
+    ADD COLUMN club_digest_weekly             BOOLEAN   NOT NULL DEFAULT FALSE,
+    ADD COLUMN club_digest_quarterly          BOOLEAN   NOT NULL DEFAULT FALSE,
+    ADD COLUMN club_digest_weekday            INTEGER   NOT NULL DEFAULT 1,
+    ADD COLUMN club_digest_hour               INTEGER   NOT NULL DEFAULT 9,
+    ADD COLUMN club_last_weekly_digest_at     TIMESTAMP NOT NULL DEFAULT now(),
+    ADD COLUMN club_last_quarterly_digest_at  TIMESTAMP NOT NULL DEFAULT now();
@@ -0,0 +1,15 @@
+CREATE TABLE api_keys
+(
+    api_key_id           BIGSERIAL PRIMARY KEY,
+    api_key_user_id      VARCHAR   NOT NULL REFERENCES discord_users (discord_user_id) ON DELETE CASCADE,
+    api_key_name         VARCHAR   NOT NULL,
+    api_key_prefix       VARCHAR   NOT NULL,
+    api_key_hash         VARCHAR   NOT NULL UNIQUE,
+    api_key_scopes       VARCHAR[] NOT NULL DEFAULT '{}',
+    api_key_club_ids     VARCHAR[] NOT NULL DEFAULT '{}',
+    api_key_rate_limit   INTEGER   NOT NULL,
+    api_key_created_at   TIMESTAMP NOT NULL DEFAULT now(),
+    api_key_last_used_at TIMESTAMP
+);
+
+CREATE INDEX api_keys_user_id_idx ON api_keys (api_key_user_id);
@@ -0,0 +1,28 @@
+CREATE TABLE webhooks
+(
+    webhook_id         BIGSERIAL PRIMARY KEY,
+    webhook_club_id    VARCHAR   NOT NULL REFERENCES clubs (club_id) ON DELETE CASCADE,
+    webhook_url        VARCHAR   NOT NULL,
+    webhook_secret     VARCHAR   NOT NULL,
+    webhook_topics     VARCHAR[] NOT NULL DEFAULT '{}',
+    webhook_created_by VARCHAR   REFERENCES discord_users (discord_user_id) ON DELETE SET NULL,
+    webhook_created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX webhooks_club_id_idx ON webhooks (webhook_club_id);
+
+CREATE TABLE webhook_deliveries
+(
+    webhook_delivery_id              BIGSERIAL PRIMARY KEY,
+    webhook_delivery_webhook_id      BIGINT    NOT NULL REFERENCES webhooks (webhook_id) ON DELETE CASCADE,
+    webhook_delivery_topic           VARCHAR   NOT NULL,
+    webhook_delivery_payload         JSONB     NOT NULL,
+    webhook_delivery_status          VARCHAR   NOT NULL DEFAULT 'pending',
+    webhook_delivery_attempts        INTEGER   NOT NULL DEFAULT 0,
+    webhook_delivery_response_status INTEGER,
+    webhook_delivery_error           VARCHAR   NOT NULL DEFAULT '',
+    webhook_delivery_created_at      TIMESTAMP NOT NULL DEFAULT now(),
+    webhook_delivery_attempted_at    TIMESTAMP
+);
+
+CREATE INDEX webhook_deliveries_webhook_id_created_at_idx ON webhook_deliveries (webhook_delivery_webhook_id, webhook_delivery_created_at DESC);
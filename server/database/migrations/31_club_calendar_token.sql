@@ -0,0 +1,2 @@
+ALTER TABLE clubs
+    ADD COLUMN club_calendar_token VARCHAR UNIQUE;
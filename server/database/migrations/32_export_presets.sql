@@ -0,0 +1,17 @@
+CREATE TABLE export_presets
+(
+    export_preset_id                      BIGSERIAL PRIMARY KEY,
+    export_preset_user_id                 VARCHAR   NOT NULL REFERENCES discord_users (discord_user_id) ON DELETE CASCADE,
+    export_preset_club_id                 VARCHAR   NOT NULL REFERENCES clubs (club_id) ON DELETE CASCADE,
+    export_preset_name                    VARCHAR   NOT NULL,
+    export_preset_included_fields         VARCHAR[] NOT NULL DEFAULT '{}',
+    export_preset_format                  VARCHAR   NOT NULL,
+    export_preset_combine_csv             BOOLEAN   NOT NULL DEFAULT FALSE,
+    export_preset_include_missing_members BOOLEAN   NOT NULL DEFAULT FALSE,
+    export_preset_event_range             VARCHAR   NOT NULL DEFAULT '',
+    export_preset_only_ca_events          BOOLEAN   NOT NULL DEFAULT FALSE,
+    export_preset_event_creator           VARCHAR   NOT NULL DEFAULT '',
+    export_preset_categories              VARCHAR[] NOT NULL DEFAULT '{}',
+    export_preset_created_at              TIMESTAMP NOT NULL DEFAULT now(),
+    UNIQUE (export_preset_user_id, export_preset_club_id, export_preset_name)
+);
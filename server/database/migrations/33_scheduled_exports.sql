@@ -0,0 +1,31 @@
+CREATE TABLE scheduled_exports
+(
+    scheduled_export_id          BIGSERIAL PRIMARY KEY,
+    scheduled_export_club_id     VARCHAR   NOT NULL REFERENCES clubs (club_id) ON DELETE CASCADE,
+    scheduled_export_preset_id   BIGINT    NOT NULL REFERENCES export_presets (export_preset_id) ON DELETE CASCADE,
+    scheduled_export_schedule    VARCHAR   NOT NULL,
+    scheduled_export_destination VARCHAR   NOT NULL,
+    scheduled_export_target      VARCHAR   NOT NULL DEFAULT '',
+    scheduled_export_next_run_at TIMESTAMP NOT NULL,
+    scheduled_export_created_by  VARCHAR   REFERENCES discord_users (discord_user_id) ON DELETE SET NULL,
+    scheduled_export_created_at  TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX scheduled_exports_club_id_idx ON scheduled_exports (scheduled_export_club_id);
+CREATE INDEX scheduled_exports_next_run_at_idx ON scheduled_exports (scheduled_export_next_run_at);
+
+CREATE TABLE scheduled_export_runs
+(
+    scheduled_export_run_id                  BIGSERIAL PRIMARY KEY,
+    scheduled_export_run_scheduled_export_id BIGINT    NOT NULL REFERENCES scheduled_exports (scheduled_export_id) ON DELETE CASCADE,
+    scheduled_export_run_scheduled_at        TIMESTAMP NOT NULL,
+    scheduled_export_run_status              VARCHAR   NOT NULL DEFAULT 'pending',
+    scheduled_export_run_attempts            INTEGER   NOT NULL DEFAULT 0,
+    scheduled_export_run_events              INTEGER   NOT NULL DEFAULT 0,
+    scheduled_export_run_file_name           VARCHAR   NOT NULL DEFAULT '',
+    scheduled_export_run_error               VARCHAR   NOT NULL DEFAULT '',
+    scheduled_export_run_created_at          TIMESTAMP NOT NULL DEFAULT now(),
+    scheduled_export_run_attempted_at        TIMESTAMP
+);
+
+CREATE INDEX scheduled_export_runs_scheduled_export_id_created_at_idx ON scheduled_export_runs (scheduled_export_run_scheduled_export_id, scheduled_export_run_created_at DESC);
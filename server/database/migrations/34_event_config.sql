@@ -0,0 +1,70 @@
+CREATE TABLE event_categories
+(
+    event_category_id         BIGSERIAL PRIMARY KEY,
+    event_category_name       VARCHAR   NOT NULL UNIQUE,
+    event_category_patterns   VARCHAR[] NOT NULL DEFAULT '{}',
+    event_category_position   INTEGER   NOT NULL DEFAULT 0,
+    event_category_created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+INSERT INTO event_categories (event_category_name, event_category_patterns, event_category_position)
+VALUES ('GO Wild Area', '{"GOWA"}', 1),
+       ('GO Fest', '{"GO Fest"}', 2),
+       ('GO Tour', '{"GO Tour"}', 3),
+       ('Community Day', '{"Community Day","Community Classic Day"}', 4),
+       ('Max Battle', '{"Max Battle Weekend","Max Battle Day","Max Weekend","Gigantamax","GMAX"}', 5),
+       ('Research Day', '{"Research Day"}', 6),
+       ('Hatch Day', '{"Hatch Day"}', 7),
+       ('Friendship Friday', '{"Friendship Friday"}', 8),
+       ('Raid Day', '{"Raid Day","Mega Raid"}', 9),
+       ('Raid Hour', '{"Raid Hour"}', 10),
+       ('Max Monday', '{"Max Monday"}', 11),
+       ('Spotlight Hour', '{"Spotlight Hour"}', 12);
+
+CREATE TABLE digital_code_exclude_patterns
+(
+    digital_code_exclude_pattern_id         BIGSERIAL PRIMARY KEY,
+    digital_code_exclude_pattern_pattern    VARCHAR   NOT NULL UNIQUE,
+    digital_code_exclude_pattern_created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+INSERT INTO digital_code_exclude_patterns (digital_code_exclude_pattern_pattern)
+VALUES ('%Friendship Friday%');
+
+CREATE TABLE configured_events
+(
+    configured_event_id         BIGSERIAL PRIMARY KEY,
+    configured_event_key        VARCHAR   NOT NULL UNIQUE,
+    configured_event_name       VARCHAR   NOT NULL,
+    configured_event_days       JSONB     NOT NULL DEFAULT '[]',
+    configured_event_position   INTEGER   NOT NULL DEFAULT 0,
+    configured_event_created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+INSERT INTO configured_events (configured_event_key, configured_event_name, configured_event_days, configured_event_position)
+VALUES ('go-fest-2026', 'GO Fest 2026', '[
+  {"label": "Saturday", "live_event_id": "b4439a90-5630-4249-bfd2-99cae5217fc1"},
+  {"label": "Sunday", "live_event_id": "0c33ab52-f475-4f74-a090-fa02d2c95b85"}
+]', 1),
+       ('go-fest-2026-road-of-legends', 'GO Fest 2026 & Road of Legends', '[
+         {"label": "Monday", "live_event_id": "a28f8b17-9a5e-4aa7-a6b4-e9180480bf99"},
+         {"label": "Tuesday", "live_event_id": "b18744df-978f-4460-aad2-faab1ffcd476"},
+         {"label": "Wednesday", "live_event_id": "744c56d0-02cf-4d0b-8923-5a5f3605cec1"},
+         {"label": "Thursday", "live_event_id": "cddafcd0-4d81-4a48-9b1e-1a123317fabd"},
+         {"label": "Friday", "live_event_id": "419a711c-79cc-4783-aac8-338edfc7d584"},
+         {"label": "Saturday", "live_event_id": "b4439a90-5630-4249-bfd2-99cae5217fc1"},
+         {"label": "Sunday", "live_event_id": "0c33ab52-f475-4f74-a090-fa02d2c95b85"}
+       ]', 2),
+       ('go-tour-2026', 'GO Tour 2026', '[
+         {"label": "Saturday", "live_event_id": "a55e08ec-ab64-42f8-a757-8132824e3a02"},
+         {"label": "Sunday", "live_event_id": "98004aa9-413b-4c3e-bba0-fca2e3e6d395"}
+       ]', 3),
+       ('go-tour-2026-road-to-kalos', 'GO Tour 2026 & Road to Kalos', '[
+         {"label": "Monday", "live_event_id": "22724136-3cd1-4ade-9f02-9c59e2d3c14a"},
+         {"label": "Tuesday", "live_event_id": "b651e53e-6b63-4891-a885-eb08701286a1"},
+         {"label": "Wednesday", "live_event_id": "64e5abc2-8c54-4391-bf9f-6f0213125655"},
+         {"label": "Thursday", "live_event_id": "968cf82e-f971-4c80-9881-7eb7fafb5232"},
+         {"label": "Friday", "live_event_id": "aaa5e6e2-5f44-401a-afc3-fddd2324bb23"},
+         {"label": "Saturday", "live_event_id": "a55e08ec-ab64-42f8-a757-8132824e3a02"},
+         {"label": "Sunday", "live_event_id": "98004aa9-413b-4c3e-bba0-fca2e3e6d395"}
+       ]', 4);
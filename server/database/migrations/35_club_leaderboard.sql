@@ -0,0 +1,2 @@
+ALTER TABLE clubs
+    ADD COLUMN club_leaderboard_opt_out BOOLEAN NOT NULL DEFAULT FALSE;
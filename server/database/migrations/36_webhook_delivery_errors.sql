@@ -0,0 +1,3 @@
+UPDATE webhook_deliveries
+SET webhook_delivery_error = substring(webhook_delivery_error FROM '^webhook responded with status [0-9]+')
+WHERE webhook_delivery_error ~ '^webhook responded with status [0-9]+: ';
@@ -0,0 +1,17 @@
+-- Cancelled jobs block the same work from being queued again until they are deleted.
+DELETE FROM jobs j
+WHERE j.job_status = 'cancelled'
+  AND EXISTS (
+    SELECT 1
+    FROM jobs o
+    WHERE o.job_kind = j.job_kind
+      AND o.job_key = j.job_key
+      AND o.job_id <> j.job_id
+      AND (o.job_status IN ('queued', 'running') OR (o.job_status = 'cancelled' AND o.job_id > j.job_id))
+);
+
+DROP INDEX jobs_active_kind_key_idx;
+
+CREATE UNIQUE INDEX jobs_active_kind_key_idx
+    ON jobs (job_kind, job_key)
+    WHERE job_status IN ('queued', 'running', 'cancelled');
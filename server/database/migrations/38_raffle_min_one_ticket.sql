@@ -0,0 +1,6 @@
+ALTER TABLE raffles
+    ADD COLUMN raffle_min_one_ticket BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- raffles created before the option always gave every entrant at least one ticket
+UPDATE raffles
+SET raffle_min_one_ticket = TRUE;
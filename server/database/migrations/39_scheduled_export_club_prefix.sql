@@ -0,0 +1,6 @@
+-- S3 key prefixes are always below the folder of the club
+UPDATE scheduled_exports
+SET scheduled_export_target = scheduled_export_club_id || '/' || scheduled_export_target
+WHERE scheduled_export_destination = 's3'
+  AND scheduled_export_target <> scheduled_export_club_id
+  AND scheduled_export_target NOT LIKE scheduled_export_club_id || '/%';
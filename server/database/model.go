@@ -10,16 +10,28 @@ import (
 )
 
 type Club struct {
-	ID                           string          `db:"club_id"`
-	Name                         string          `db:"club_name"`
-	AvatarURL                    string          `db:"club_avatar_url"`
-	CreatorID                    string          `db:"club_creator_id"`
-	CreatedByCommunityAmbassador bool            `db:"club_created_by_community_ambassador"`
-	ImportedAt                   time.Time       `db:"club_imported_at"`
-	RawJSON                      json.RawMessage `db:"club_raw_json"`
-	AutoEventImport              bool            `db:"club_auto_event_import"`
-	ClubVerificationChannelID    *string         `db:"club_verification_channel_id"`
-	LastAutoEventImportedAt      time.Time       `db:"club_last_auto_event_imported_at"`
+	ID                                string          `db:"club_id"`
+	Name                              string          `db:"club_name"`
+	AvatarURL                         string          `db:"club_avatar_url"`
+	CreatorID                         string          `db:"club_creator_id"`
+	CreatedByCommunityAmbassador      bool            `db:"club_created_by_community_ambassador"`
+	ImportedAt                        time.Time       `db:"club_imported_at"`
+	RawJSON                           json.RawMessage `db:"club_raw_json"`
+	AutoEventImport                   bool            `db:"club_auto_event_import"`
+	ClubVerificationChannelID         *string         `db:"club_verification_channel_id"`
+	LastAutoEventImportedAt           time.Time       `db:"club_last_auto_event_imported_at"`
+	RaffleBlockedMembers              pq.StringArray  `db:"club_raffle_blocked_members"`
+	RaffleCooldownDays                int             `db:"club_raffle_cooldown_days"`
+	RaffleExcludeCommunityAmbassadors bool            `db:"club_raffle_exclude_community_ambassadors"`
+	DigestWebhookURL                  *string         `db:"club_digest_webhook_url"`
+	DigestWeekly                      bool            `db:"club_digest_weekly"`
+	DigestQuarterly                   bool            `db:"club_digest_quarterly"`
+	DigestWeekday                     time.Weekday    `db:"club_digest_weekday"`
+	DigestHour                        int             `db:"club_digest_hour"`
+	LastWeeklyDigestAt                time.Time       `db:"club_last_weekly_digest_at"`
+	LastQuarterlyDigestAt             time.Time       `db:"club_last_quarterly_digest_at"`
+	CalendarToken                     *string         `db:"club_calendar_token"`
+	LeaderboardOptOut                 bool            `db:"club_leaderboard_opt_out"`
 }
 
 type Event struct {
@@ -104,6 +116,31 @@ type EventRSVP struct {
 	ImportedAt time.Time `db:"event_rsvp_imported_at"`
 }
 
+// EventRSVPHistory is one observed RSVP status transition. OldStatus is nil for the first time a member was seen on an event.
+type EventRSVPHistory struct {
+	ID         int       `db:"event_rsvp_history_id"`
+	EventID    string    `db:"event_rsvp_history_event_id"`
+	MemberID   string    `db:"event_rsvp_history_member_id"`
+	OldStatus  *string   `db:"event_rsvp_history_old_status"`
+	NewStatus  string    `db:"event_rsvp_history_new_status"`
+	ObservedAt time.Time `db:"event_rsvp_history_observed_at"`
+}
+
+type EventRSVPHistoryWithMember struct {
+	EventRSVPHistory
+	Member
+}
+
+// EventRSVPExport is an RSVP together with its event, member and the event creator, as read by StreamEventRSVPExports.
+// The raw JSON of the event and member is not selected.
+type EventRSVPExport struct {
+	Event
+	EventRSVP
+	Member
+	CreatorUsername    string `db:"creator_username"`
+	CreatorDisplayName string `db:"creator_display_name"`
+}
+
 // LiveEventMemberRSVP is one member's aggregated RSVP status within a single
 // Campfire live event (across all of that live event's meetups for a club).
 // StatusRank is 2 for CHECKED_IN, 1 for ACCEPTED only, 0 otherwise.
@@ -114,29 +151,99 @@ type LiveEventMemberRSVP struct {
 	StatusRank  int    `db:"status_rank"`
 }
 
+type RaffleWeighting string
+
+const (
+	// RaffleWeightingNone gives every member one ticket, or one ticket per event if single entry is disabled.
+	RaffleWeightingNone RaffleWeighting = "none"
+	// RaffleWeightingCheckIns gives every member one ticket per check-in across the raffle events.
+	RaffleWeightingCheckIns RaffleWeighting = "check_ins"
+)
+
 type Raffle struct {
-	ID            int            `db:"raffle_id"`
-	UserID        string         `db:"raffle_user_id"`
-	Events        pq.StringArray `db:"raffle_events"`
-	WinnerCount   int            `db:"raffle_winner_count"`
-	OnlyCheckedIn bool           `db:"raffle_only_checked_in"`
-	SingleEntry   bool           `db:"raffle_single_entry"`
-	CreatedAt     time.Time      `db:"raffle_created_at"`
+	ID               int             `db:"raffle_id"`
+	UserID           string          `db:"raffle_user_id"`
+	Events           pq.StringArray  `db:"raffle_events"`
+	WinnerCount      int             `db:"raffle_winner_count"`
+	OnlyCheckedIn    bool            `db:"raffle_only_checked_in"`
+	SingleEntry      bool            `db:"raffle_single_entry"`
+	CreatedAt        time.Time       `db:"raffle_created_at"`
+	Weighting        RaffleWeighting `db:"raffle_weighting"`
+	BonusMinCheckIns int             `db:"raffle_bonus_min_check_ins"`
+	BonusTickets     int             `db:"raffle_bonus_tickets"`
+	MaxTickets       int             `db:"raffle_max_tickets"`
+	// MinOneTicket gives entrants without any ticket from the weighting one ticket.
+	MinOneTicket bool `db:"raffle_min_one_ticket"`
+	// Seed is the secret seed of the next draw, it is only revealed in the RaffleDraw using it.
+	Seed           string `db:"raffle_seed"`
+	SeedCommitment string `db:"raffle_seed_commitment"`
+	// ClubID is set for raffles run from a club, which applies the raffle exclusion rules of the club.
+	ClubID *string `db:"raffle_club_id"`
+	// RewardID is the reward whose codes are handed to confirmed winners without a prize tier.
+	RewardID *int `db:"raffle_reward_id"`
+}
+
+type RaffleDrawEntrant struct {
+	MemberID    string `json:"member_id"`
+	DisplayName string `json:"display_name"`
+	Tickets     int    `json:"tickets"`
+}
+
+type RaffleDraw struct {
+	ID             int                               `db:"raffle_draw_id"`
+	RaffleID       int                               `db:"raffle_draw_raffle_id"`
+	SeedCommitment string                            `db:"raffle_draw_seed_commitment"`
+	Seed           string                            `db:"raffle_draw_seed"`
+	WinnerCount    int                               `db:"raffle_draw_winner_count"`
+	Entrants       xpgtype.JSON[[]RaffleDrawEntrant] `db:"raffle_draw_entrants"`
+	Winners        pq.StringArray                    `db:"raffle_draw_winners"`
+	CreatedAt      time.Time                         `db:"raffle_draw_created_at"`
 }
 
 type RaffleWinner struct {
-	RaffleID  int       `db:"raffle_winner_raffle_id"`
-	MemberID  string    `db:"raffle_winner_member_id"`
-	Confirmed bool      `db:"raffle_winner_confirmed"`
-	Past      bool      `db:"raffle_winner_past"`
-	CreatedAt time.Time `db:"raffle_winner_created_at"`
+	RaffleID     int       `db:"raffle_winner_raffle_id"`
+	MemberID     string    `db:"raffle_winner_member_id"`
+	Confirmed    bool      `db:"raffle_winner_confirmed"`
+	Past         bool      `db:"raffle_winner_past"`
+	CreatedAt    time.Time `db:"raffle_winner_created_at"`
+	PrizeTierID  *int      `db:"raffle_winner_prize_tier_id"`
+	RewardCodeID *int      `db:"raffle_winner_reward_code_id"`
 }
 
 type RaffleWinnerWithMember struct {
 	RaffleWinner
 	Member
-	Accepted int `db:"accepted"`
-	CheckIns int `db:"check_ins"`
+	Accepted             int    `db:"accepted"`
+	CheckIns             int    `db:"check_ins"`
+	PrizeTierName        string `db:"prize_tier_name"`
+	RewardID             *int   `db:"reward_id"`
+	RewardCode           string `db:"reward_code"`
+	RewardCodeRedeemCode string `db:"reward_code_redeem_code"`
+}
+
+// RewardCodeRaffleWinner is the raffle winner a reward code was handed to.
+type RewardCodeRaffleWinner struct {
+	Member
+	RaffleID     int        `db:"raffle_id"`
+	ClubID       *string    `db:"raffle_club_id"`
+	RewardCodeID int        `db:"reward_code_id"`
+	RewardID     int        `db:"reward_id"`
+	RewardName   string     `db:"reward_name"`
+	RedeemedAt   *time.Time `db:"reward_code_redeemed_at"`
+}
+
+type RafflePrizeTier struct {
+	ID       int    `db:"raffle_prize_tier_id"`
+	RaffleID int    `db:"raffle_prize_tier_raffle_id"`
+	Position int    `db:"raffle_prize_tier_position"`
+	Name     string `db:"raffle_prize_tier_name"`
+	Count    int    `db:"raffle_prize_tier_count"`
+	RewardID *int   `db:"raffle_prize_tier_reward_id"`
+}
+
+type RafflePrizeTierWithReward struct {
+	RafflePrizeTier
+	RewardName string `db:"reward_name"`
 }
 
 type DiscordUser struct {
@@ -197,6 +304,41 @@ type EventState struct {
 	RSVPs   []EventRSVP `json:"rsvps"`
 }
 
+type JobKind string
+
+const (
+	JobKindImportClub         JobKind = "import_club"
+	JobKindImportClubEvents   JobKind = "import_club_events"
+	JobKindUpdateEvent        JobKind = "update_event"
+	JobKindSendClubDigest     JobKind = "send_club_digest"
+	JobKindDeliverWebhook     JobKind = "deliver_webhook"
+	JobKindRunScheduledExport JobKind = "run_scheduled_export"
+)
+
+type JobStatus string
+
+const (
+	JobStatusQueued    JobStatus = "queued"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusCompleted JobStatus = "completed"
+	JobStatusFailed    JobStatus = "failed"
+	JobStatusCancelled JobStatus = "cancelled"
+)
+
+type Job struct {
+	ID          int       `db:"job_id"`
+	Kind        JobKind   `db:"job_kind"`
+	Key         string    `db:"job_key"`
+	Priority    int       `db:"job_priority"`
+	Status      JobStatus `db:"job_status"`
+	Attempts    int       `db:"job_attempts"`
+	MaxAttempts int       `db:"job_max_attempts"`
+	NextRunAt   time.Time `db:"job_next_run_at"`
+	LastError   string    `db:"job_last_error"`
+	CreatedAt   time.Time `db:"job_created_at"`
+	UpdatedAt   time.Time `db:"job_updated_at"`
+}
+
 type RewardUser struct {
 	ID           int       `db:"reward_user_id"`
 	CreatedAt    time.Time `db:"reward_user_created_at"`
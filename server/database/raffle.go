@@ -2,7 +2,11 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"log/slog"
+	"time"
 
 	"github.com/lib/pq"
 )
@@ -63,8 +67,8 @@ func (d *Database) AppendRaffleEvents(ctx context.Context, raffleID int, eventID
 
 func (d *Database) InsertRaffle(ctx context.Context, raffle Raffle) (int, error) {
 	query := `
-		INSERT INTO raffles (raffle_user_id, raffle_events, raffle_winner_count, raffle_only_checked_in, raffle_single_entry)
-		VALUES (:raffle_user_id, :raffle_events, :raffle_winner_count, :raffle_only_checked_in, :raffle_single_entry)
+		INSERT INTO raffles (raffle_user_id, raffle_events, raffle_winner_count, raffle_only_checked_in, raffle_single_entry, raffle_weighting, raffle_bonus_min_check_ins, raffle_bonus_tickets, raffle_max_tickets, raffle_min_one_ticket, raffle_seed, raffle_seed_commitment, raffle_club_id, raffle_reward_id)
+		VALUES (:raffle_user_id, :raffle_events, :raffle_winner_count, :raffle_only_checked_in, :raffle_single_entry, :raffle_weighting, :raffle_bonus_min_check_ins, :raffle_bonus_tickets, :raffle_max_tickets, :raffle_min_one_ticket, :raffle_seed, :raffle_seed_commitment, :raffle_club_id, :raffle_reward_id)
 		RETURNING raffle_id
 	`
 
@@ -99,12 +103,18 @@ func (d *Database) GetRaffleWinners(ctx context.Context, raffleID int) ([]Raffle
 				WHERE event_rsvp_event_id = ANY(raffle_events)
 				AND event_rsvp_member_id = raffle_winner_member_id
 				AND event_rsvp_status IN ('CHECKED_IN', 'ACCEPTED')
-			) AS accepted
+			) AS accepted,
+			COALESCE(raffle_prize_tier_name, '') AS prize_tier_name,
+			COALESCE(raffle_prize_tier_reward_id, CASE WHEN raffle_winner_prize_tier_id IS NULL THEN raffle_reward_id END) AS reward_id,
+			COALESCE(reward_code_code, '') AS reward_code,
+			COALESCE(reward_code_redeem_code, '') AS reward_code_redeem_code
 		FROM raffle_winners
 		JOIN members ON raffle_winner_member_id = member_id
 		JOIN raffles ON raffle_winner_raffle_id = raffle_id
+		LEFT JOIN raffle_prize_tiers ON raffle_winner_prize_tier_id = raffle_prize_tier_id
+		LEFT JOIN reward_codes ON raffle_winner_reward_code_id = reward_code_id
 		WHERE raffle_winner_raffle_id = $1
-		ORDER BY raffle_winner_created_at DESC
+		ORDER BY raffle_winner_created_at DESC, raffle_prize_tier_position NULLS LAST, raffle_winner_member_id
 	`
 
 	var winners []RaffleWinnerWithMember
@@ -128,18 +138,10 @@ func (d *Database) DeleteNotConfirmedRaffleWinners(ctx context.Context, raffleID
 	return nil
 }
 
-func (d *Database) InsertRaffleWinners(ctx context.Context, raffleID int, memberIDs []string) error {
-	winners := make([]RaffleWinner, len(memberIDs))
-	for i, id := range memberIDs {
-		winners[i] = RaffleWinner{
-			RaffleID: raffleID,
-			MemberID: id,
-		}
-	}
-
+func (d *Database) InsertRaffleWinners(ctx context.Context, winners []RaffleWinner) error {
 	query := `
-		INSERT INTO raffle_winners (raffle_winner_raffle_id, raffle_winner_member_id)
-		VALUES (:raffle_winner_raffle_id, :raffle_winner_member_id)
+		INSERT INTO raffle_winners (raffle_winner_raffle_id, raffle_winner_member_id, raffle_winner_prize_tier_id)
+		VALUES (:raffle_winner_raffle_id, :raffle_winner_member_id, :raffle_winner_prize_tier_id)
 	`
 
 	if _, err := d.db.NamedExecContext(ctx, query, winners); err != nil {
@@ -149,18 +151,105 @@ func (d *Database) InsertRaffleWinners(ctx context.Context, raffleID int, member
 	return nil
 }
 
-func (d *Database) ConfirmRaffleWinner(ctx context.Context, raffleID int, memberID string) error {
+// ConfirmRaffleWinner confirms the winner and claims the next unredeemed code of their reward.
+// The reward is the one of their prize tier, or the one of the raffle for winners without a prize tier.
+// The code is marked as redeemed by redeemedBy and its ID is returned. Winners are still confirmed when no code is left.
+// confirmed reports whether the winner was confirmed by this call and not already before.
+func (d *Database) ConfirmRaffleWinner(ctx context.Context, raffleID int, memberID string, redeemedBy *string) (rewardCodeID *int, confirmed bool, err error) {
+	tx, err := d.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			slog.ErrorContext(ctx, "failed to rollback transaction", "error", err)
+		}
+	}()
+
 	query := `
+		SELECT
+			raffle_winner_confirmed,
+			raffle_winner_reward_code_id,
+			COALESCE(raffle_prize_tier_reward_id, CASE WHEN raffle_winner_prize_tier_id IS NULL THEN raffle_reward_id END) AS reward_id
+		FROM raffle_winners
+		JOIN raffles ON raffle_winner_raffle_id = raffle_id
+		LEFT JOIN raffle_prize_tiers ON raffle_winner_prize_tier_id = raffle_prize_tier_id
+		WHERE raffle_winner_raffle_id = $1 AND raffle_winner_member_id = $2
+		FOR UPDATE OF raffle_winners
+	`
+
+	var winner struct {
+		Confirmed    bool `db:"raffle_winner_confirmed"`
+		RewardCodeID *int `db:"raffle_winner_reward_code_id"`
+		RewardID     *int `db:"reward_id"`
+	}
+	if err = tx.GetContext(ctx, &winner, query, raffleID, memberID); err != nil {
+		return nil, false, fmt.Errorf("failed to get raffle winner: %w", err)
+	}
+	if winner.Confirmed {
+		return winner.RewardCodeID, false, nil
+	}
+
+	if winner.RewardID != nil {
+		query = `
+			UPDATE reward_codes
+			SET reward_code_redeemed_at = now(),
+				reward_code_redeemed_by = $2
+			WHERE reward_code_id = (
+				SELECT reward_code_id
+				FROM reward_codes
+				WHERE reward_code_reward_id = $1 AND reward_code_redeemed_at IS NULL
+				ORDER BY reward_code_imported_at, reward_code_id
+				LIMIT 1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING reward_code_id
+		`
+
+		var codeID int
+		if err = tx.GetContext(ctx, &codeID, query, *winner.RewardID, redeemedBy); err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, false, fmt.Errorf("failed to claim reward code: %w", err)
+		}
+		if err == nil {
+			rewardCodeID = &codeID
+		}
+	}
+
+	query = `
 		UPDATE raffle_winners
-		SET raffle_winner_confirmed = TRUE
+		SET raffle_winner_confirmed = TRUE,
+			raffle_winner_reward_code_id = $3
 		WHERE raffle_winner_raffle_id = $1 AND raffle_winner_member_id = $2
 	`
+	if _, err = tx.ExecContext(ctx, query, raffleID, memberID, rewardCodeID); err != nil {
+		return nil, false, fmt.Errorf("failed to confirm raffle winner: %w", err)
+	}
 
-	if _, err := d.db.ExecContext(ctx, query, raffleID, memberID); err != nil {
-		return fmt.Errorf("failed to confirm raffle winner: %w", err)
+	if err = tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	return nil
+	return rewardCodeID, true, nil
+}
+
+func (d *Database) GetRewardCodeRaffleWinner(ctx context.Context, rewardCodeID int) (*RewardCodeRaffleWinner, error) {
+	query := `
+		SELECT members.*, raffle_id, raffle_club_id, reward_code_id, reward_id, reward_name, reward_code_redeemed_at
+		FROM raffle_winners
+		JOIN raffles ON raffle_winner_raffle_id = raffle_id
+		JOIN members ON raffle_winner_member_id = member_id
+		JOIN reward_codes ON raffle_winner_reward_code_id = reward_code_id
+		JOIN rewards ON reward_code_reward_id = reward_id
+		WHERE reward_code_id = $1
+	`
+
+	var winner RewardCodeRaffleWinner
+	if err := d.db.GetContext(ctx, &winner, query, rewardCodeID); err != nil {
+		return nil, fmt.Errorf("failed to get reward code raffle winner: %w", err)
+	}
+
+	return &winner, nil
 }
 
 func (d *Database) DeleteUnconfirmedRaffleWinners(ctx context.Context, raffleID int) error {
@@ -189,3 +278,23 @@ func (d *Database) MarkRaffleWinnersAsPast(ctx context.Context, raffleID int) er
 
 	return nil
 }
+
+// GetClubRaffleWinnerIDs returns the members who won a raffle of the club since the given time.
+// Only confirmed winners count, as unconfirmed winners are replaced on rerun.
+func (d *Database) GetClubRaffleWinnerIDs(ctx context.Context, clubID string, since time.Time) ([]string, error) {
+	query := `
+		SELECT DISTINCT raffle_winner_member_id
+		FROM raffle_winners
+		JOIN raffles ON raffle_winner_raffle_id = raffle_id
+		WHERE raffle_club_id = $1
+		AND raffle_winner_confirmed = TRUE
+		AND raffle_winner_created_at >= $2
+	`
+
+	var memberIDs []string
+	if err := d.db.SelectContext(ctx, &memberIDs, query, clubID, since); err != nil {
+		return nil, fmt.Errorf("failed to get club raffle winners: %w", err)
+	}
+
+	return memberIDs, nil
+}
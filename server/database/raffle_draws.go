@@ -0,0 +1,100 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// ErrRaffleSeedUsed is returned when the seed of a draw was already used by another draw of the raffle.
+var ErrRaffleSeedUsed = errors.New("the seed of the raffle was already used by another draw, please try again")
+
+// InsertRaffleDraw records a draw and replaces the seed of the raffle with the next one in the same transaction,
+// so a revealed seed is never used again. It returns ErrRaffleSeedUsed if the seed of the draw is not the current seed of the raffle anymore.
+func (d *Database) InsertRaffleDraw(ctx context.Context, draw RaffleDraw, nextSeed string, nextSeedCommitment string) error {
+	tx, err := d.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			slog.ErrorContext(ctx, "failed to rollback transaction", "error", err)
+		}
+	}()
+
+	query := `
+		INSERT INTO raffle_draws (raffle_draw_raffle_id, raffle_draw_seed_commitment, raffle_draw_seed, raffle_draw_winner_count, raffle_draw_entrants, raffle_draw_winners)
+		VALUES (:raffle_draw_raffle_id, :raffle_draw_seed_commitment, :raffle_draw_seed, :raffle_draw_winner_count, :raffle_draw_entrants, :raffle_draw_winners)
+	`
+	if _, err = tx.NamedExecContext(ctx, query, draw); err != nil {
+		return fmt.Errorf("failed to insert raffle draw: %w", err)
+	}
+
+	query = `
+		UPDATE raffles
+		SET raffle_seed = $2, raffle_seed_commitment = $3
+		WHERE raffle_id = $1 AND raffle_seed = $4
+	`
+	res, err := tx.ExecContext(ctx, query, draw.RaffleID, nextSeed, nextSeedCommitment, draw.Seed)
+	if err != nil {
+		return fmt.Errorf("failed to update raffle seed: %w", err)
+	}
+	if rows, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to get updated raffle seeds: %w", err)
+	} else if rows != 1 {
+		return ErrRaffleSeedUsed
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// CommitRaffleSeed sets the seed of the next draw of a raffle which has no seed yet.
+func (d *Database) CommitRaffleSeed(ctx context.Context, raffleID int, seed string, seedCommitment string) error {
+	query := `
+		UPDATE raffles
+		SET raffle_seed = $2, raffle_seed_commitment = $3
+		WHERE raffle_id = $1 AND raffle_seed = ''
+	`
+
+	if _, err := d.db.ExecContext(ctx, query, raffleID, seed, seedCommitment); err != nil {
+		return fmt.Errorf("failed to commit raffle seed: %w", err)
+	}
+
+	return nil
+}
+
+func (d *Database) GetRaffleDrawCount(ctx context.Context, raffleID int) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM raffle_draws
+		WHERE raffle_draw_raffle_id = $1
+	`
+
+	var count int
+	if err := d.db.GetContext(ctx, &count, query, raffleID); err != nil {
+		return 0, fmt.Errorf("failed to get raffle draw count: %w", err)
+	}
+
+	return count, nil
+}
+
+func (d *Database) GetRaffleDraws(ctx context.Context, raffleID int) ([]RaffleDraw, error) {
+	query := `
+		SELECT * FROM raffle_draws
+		WHERE raffle_draw_raffle_id = $1
+		ORDER BY raffle_draw_created_at, raffle_draw_id
+	`
+
+	var draws []RaffleDraw
+	if err := d.db.SelectContext(ctx, &draws, query, raffleID); err != nil {
+		return nil, fmt.Errorf("failed to get raffle draws: %w", err)
+	}
+
+	return draws, nil
+}
@@ -0,0 +1,40 @@
+package database
+
+import (
+	"context"
+	"fmt"
+)
+
+func (d *Database) InsertRafflePrizeTiers(ctx context.Context, tiers []RafflePrizeTier) error {
+	if len(tiers) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO raffle_prize_tiers (raffle_prize_tier_raffle_id, raffle_prize_tier_position, raffle_prize_tier_name, raffle_prize_tier_count, raffle_prize_tier_reward_id)
+		VALUES (:raffle_prize_tier_raffle_id, :raffle_prize_tier_position, :raffle_prize_tier_name, :raffle_prize_tier_count, :raffle_prize_tier_reward_id)
+	`
+
+	if _, err := d.db.NamedExecContext(ctx, query, tiers); err != nil {
+		return fmt.Errorf("failed to insert raffle prize tiers: %w", err)
+	}
+
+	return nil
+}
+
+func (d *Database) GetRafflePrizeTiers(ctx context.Context, raffleID int) ([]RafflePrizeTierWithReward, error) {
+	query := `
+		SELECT raffle_prize_tiers.*, COALESCE(reward_name, '') AS reward_name
+		FROM raffle_prize_tiers
+		LEFT JOIN rewards ON raffle_prize_tier_reward_id = reward_id
+		WHERE raffle_prize_tier_raffle_id = $1
+		ORDER BY raffle_prize_tier_position
+	`
+
+	var tiers []RafflePrizeTierWithReward
+	if err := d.db.SelectContext(ctx, &tiers, query, raffleID); err != nil {
+		return nil, fmt.Errorf("failed to get raffle prize tiers: %w", err)
+	}
+
+	return tiers, nil
+}
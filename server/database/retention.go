@@ -0,0 +1,124 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// CohortRetention is the number of members of a cohort who checked in the given number of months after the month of their first check-in at the club.
+// Month 0 is the size of the cohort.
+type CohortRetention struct {
+	Cohort  time.Time `db:"cohort"`
+	Month   int       `db:"cohort_month"`
+	Members int       `db:"members"`
+}
+
+// EventRetention is an event with the number of checked in members who checked in at the club for the first time and who returned.
+type EventRetention struct {
+	Event
+	NewMembers       int `db:"new_members"`
+	ReturningMembers int `db:"returning_members"`
+}
+
+// LapsedMember is a member with their check-ins at the club and the time of their last check-in.
+type LapsedMember struct {
+	Member
+	CheckIns      int       `db:"check_ins"`
+	LastCheckInAt time.Time `db:"last_check_in_at"`
+}
+
+// GetClubCohortRetention groups the members of the club by the month of their first check-in and counts how many of them checked in
+// in each of the following months, up to the given number of months. Only cohorts starting between from and to are returned.
+func (d *Database) GetClubCohortRetention(ctx context.Context, clubID string, from time.Time, to time.Time, months int) ([]CohortRetention, error) {
+	query := `
+		WITH check_in_months AS (
+			SELECT DISTINCT er.event_rsvp_member_id AS member_id, date_trunc('month', e.event_time) AS month
+			FROM event_rsvps er
+			JOIN events e ON er.event_rsvp_event_id = e.event_id
+			WHERE e.event_club_id = $1
+			AND er.event_rsvp_status = 'CHECKED_IN'
+		), cohorts AS (
+			SELECT member_id, MIN(month) AS cohort
+			FROM check_in_months
+			GROUP BY member_id
+		)
+		SELECT c.cohort,
+			((EXTRACT(YEAR FROM cm.month) - EXTRACT(YEAR FROM c.cohort)) * 12 + EXTRACT(MONTH FROM cm.month) - EXTRACT(MONTH FROM c.cohort))::INT AS cohort_month,
+			COUNT(*) AS members
+		FROM cohorts c
+		JOIN check_in_months cm ON cm.member_id = c.member_id
+		WHERE cm.month <= c.cohort + make_interval(months => $4)
+		AND ($2 = '0001-01-01 00:00:00'::timestamp OR c.cohort >= date_trunc('month', $2::timestamp))
+		AND ($3 = '0001-01-01 00:00:00'::timestamp OR c.cohort <= $3)
+		GROUP BY c.cohort, cohort_month
+		ORDER BY c.cohort, cohort_month
+	`
+
+	var cohorts []CohortRetention
+	if err := d.db.SelectContext(ctx, &cohorts, query, clubID, from, to, months); err != nil {
+		return nil, fmt.Errorf("failed to get club cohort retention: %w", err)
+	}
+
+	return cohorts, nil
+}
+
+// GetClubEventRetention returns the events of the club with how many of their checked in members checked in at the club for the first time.
+func (d *Database) GetClubEventRetention(ctx context.Context, clubID string, from time.Time, to time.Time, caOnly bool, eventCreator string) ([]EventRetention, error) {
+	query := `
+		WITH first_check_ins AS (
+			SELECT DISTINCT ON (er.event_rsvp_member_id) er.event_rsvp_member_id AS member_id, e.event_id
+			FROM event_rsvps er
+			JOIN events e ON er.event_rsvp_event_id = e.event_id
+			WHERE e.event_club_id = $1
+			AND er.event_rsvp_status = 'CHECKED_IN'
+			ORDER BY er.event_rsvp_member_id, e.event_time, e.event_id
+		)
+		SELECT e.*,
+			COUNT(CASE WHEN f.event_id = e.event_id THEN 1 END) AS new_members,
+			COUNT(CASE WHEN f.event_id != e.event_id THEN 1 END) AS returning_members
+		FROM events e
+		JOIN event_rsvps er ON er.event_rsvp_event_id = e.event_id
+		JOIN first_check_ins f ON f.member_id = er.event_rsvp_member_id
+		WHERE e.event_club_id = $1
+		AND er.event_rsvp_status = 'CHECKED_IN'
+		AND ($2 = '0001-01-01 00:00:00'::timestamp OR e.event_time >= $2)
+		AND ($3 = '0001-01-01 00:00:00'::timestamp OR e.event_time <= $3)
+		AND (NOT $4 OR e.event_created_by_community_ambassador = TRUE)
+		AND ($5 = '' OR e.event_creator_id = $5)
+		GROUP BY e.event_id
+		ORDER BY e.event_time DESC
+	`
+
+	var events []EventRetention
+	if err := d.db.SelectContext(ctx, &events, query, clubID, from, to, caOnly, eventCreator); err != nil {
+		return nil, fmt.Errorf("failed to get club event retention: %w", err)
+	}
+
+	return events, nil
+}
+
+// GetClubLapsedMembers returns the members with at least minCheckIns check-ins at the club whose last check-in was before the given time, the most check-ins first.
+func (d *Database) GetClubLapsedMembers(ctx context.Context, clubID string, minCheckIns int, before time.Time, limit int) ([]LapsedMember, error) {
+	query := `
+		SELECT m.*,
+			COUNT(*) AS check_ins,
+			MAX(e.event_time) AS last_check_in_at
+		FROM event_rsvps er
+		JOIN events e ON er.event_rsvp_event_id = e.event_id
+		JOIN members m ON er.event_rsvp_member_id = m.member_id
+		WHERE e.event_club_id = $1
+		AND er.event_rsvp_status = 'CHECKED_IN'
+		GROUP BY m.member_id
+		HAVING COUNT(*) >= $2 AND MAX(e.event_time) < $3
+		ORDER BY check_ins DESC, last_check_in_at DESC, m.member_id
+		LIMIT $4
+	`
+
+	var members []LapsedMember
+	if err := d.db.SelectContext(ctx, &members, query, clubID, minCheckIns, before, limit); err != nil {
+		return nil, fmt.Errorf("failed to get club lapsed members: %w", err)
+	}
+
+	return members, nil
+}
@@ -0,0 +1,280 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+type ScheduledExportDestination string
+
+const (
+	ScheduledExportDestinationDiscord ScheduledExportDestination = "discord"
+	ScheduledExportDestinationS3      ScheduledExportDestination = "s3"
+)
+
+type ScheduledExportRunStatus string
+
+const (
+	ScheduledExportRunStatusPending   ScheduledExportRunStatus = "pending"
+	ScheduledExportRunStatusSucceeded ScheduledExportRunStatus = "succeeded"
+	ScheduledExportRunStatusFailed    ScheduledExportRunStatus = "failed"
+)
+
+// ScheduledExport runs an export preset on a cron schedule and delivers the file to the destination.
+// Target is the Discord webhook URL or the key prefix in the configured S3 bucket.
+type ScheduledExport struct {
+	ID          int                        `db:"scheduled_export_id"`
+	ClubID      string                     `db:"scheduled_export_club_id"`
+	PresetID    int                        `db:"scheduled_export_preset_id"`
+	Schedule    string                     `db:"scheduled_export_schedule"`
+	Destination ScheduledExportDestination `db:"scheduled_export_destination"`
+	Target      string                     `db:"scheduled_export_target"`
+	NextRunAt   time.Time                  `db:"scheduled_export_next_run_at"`
+	CreatedBy   *string                    `db:"scheduled_export_created_by"`
+	CreatedAt   time.Time                  `db:"scheduled_export_created_at"`
+}
+
+type ScheduledExportWithPreset struct {
+	ScheduledExport
+	ExportPreset
+}
+
+type ScheduledExportRun struct {
+	ID                int                      `db:"scheduled_export_run_id"`
+	ScheduledExportID int                      `db:"scheduled_export_run_scheduled_export_id"`
+	ScheduledAt       time.Time                `db:"scheduled_export_run_scheduled_at"`
+	Status            ScheduledExportRunStatus `db:"scheduled_export_run_status"`
+	Attempts          int                      `db:"scheduled_export_run_attempts"`
+	Events            int                      `db:"scheduled_export_run_events"`
+	FileName          string                   `db:"scheduled_export_run_file_name"`
+	Error             string                   `db:"scheduled_export_run_error"`
+	CreatedAt         time.Time                `db:"scheduled_export_run_created_at"`
+	AttemptedAt       *time.Time               `db:"scheduled_export_run_attempted_at"`
+}
+
+type ScheduledExportRunWithExport struct {
+	ScheduledExportRun
+	ScheduledExport
+	ExportPreset
+}
+
+func (d *Database) InsertScheduledExport(ctx context.Context, scheduledExport ScheduledExport) (int, error) {
+	query := `
+		INSERT INTO scheduled_exports (scheduled_export_club_id, scheduled_export_preset_id, scheduled_export_schedule, scheduled_export_destination,
+			scheduled_export_target, scheduled_export_next_run_at, scheduled_export_created_by)
+		VALUES (:scheduled_export_club_id, :scheduled_export_preset_id, :scheduled_export_schedule, :scheduled_export_destination,
+			:scheduled_export_target, :scheduled_export_next_run_at, :scheduled_export_created_by)
+		RETURNING scheduled_export_id
+	`
+
+	query, args, err := d.db.BindNamed(query, scheduledExport)
+	if err != nil {
+		return 0, fmt.Errorf("failed to bind named query: %w", err)
+	}
+
+	var id int
+	if err = d.db.GetContext(ctx, &id, query, args...); err != nil {
+		return 0, fmt.Errorf("failed to insert scheduled export: %w", err)
+	}
+
+	return id, nil
+}
+
+func (d *Database) GetClubScheduledExports(ctx context.Context, clubID string) ([]ScheduledExportWithPreset, error) {
+	query := `
+		SELECT scheduled_exports.*, export_presets.*
+		FROM scheduled_exports
+		JOIN export_presets ON scheduled_export_preset_id = export_preset_id
+		WHERE scheduled_export_club_id = $1
+		ORDER BY scheduled_export_next_run_at, scheduled_export_id
+	`
+
+	var scheduledExports []ScheduledExportWithPreset
+	if err := d.db.SelectContext(ctx, &scheduledExports, query, clubID); err != nil {
+		return nil, fmt.Errorf("failed to get club scheduled exports: %w", err)
+	}
+
+	return scheduledExports, nil
+}
+
+func (d *Database) GetScheduledExport(ctx context.Context, id int, clubID string) (*ScheduledExport, error) {
+	query := `
+		SELECT *
+		FROM scheduled_exports
+		WHERE scheduled_export_id = $1 AND scheduled_export_club_id = $2
+	`
+
+	var scheduledExport ScheduledExport
+	if err := d.db.GetContext(ctx, &scheduledExport, query, id, clubID); err != nil {
+		return nil, fmt.Errorf("failed to get scheduled export: %w", err)
+	}
+
+	return &scheduledExport, nil
+}
+
+func (d *Database) DeleteScheduledExport(ctx context.Context, id int, clubID string) error {
+	query := `
+		DELETE FROM scheduled_exports
+		WHERE scheduled_export_id = $1 AND scheduled_export_club_id = $2
+	`
+
+	if _, err := d.db.ExecContext(ctx, query, id, clubID); err != nil {
+		return fmt.Errorf("failed to delete scheduled export: %w", err)
+	}
+
+	return nil
+}
+
+// GetDueScheduledExports returns the scheduled exports whose next run is due.
+func (d *Database) GetDueScheduledExports(ctx context.Context) ([]ScheduledExport, error) {
+	query := `
+		SELECT *
+		FROM scheduled_exports
+		WHERE scheduled_export_next_run_at <= now()
+	`
+
+	var scheduledExports []ScheduledExport
+	if err := d.db.SelectContext(ctx, &scheduledExports, query); err != nil {
+		return nil, fmt.Errorf("failed to get due scheduled exports: %w", err)
+	}
+
+	return scheduledExports, nil
+}
+
+// ScheduleExportRun creates a pending run for the due run of the scheduled export, moves its next run to nextRunAt
+// and queues job with the run ID as key to run it. The run is only created if the job is queued as well.
+// It returns sql.ErrNoRows if the run was already scheduled since the scheduled export was read.
+func (d *Database) ScheduleExportRun(ctx context.Context, scheduledExport ScheduledExport, nextRunAt time.Time, job Job) (int, error) {
+	query := `
+		WITH scheduled AS (
+			UPDATE scheduled_exports
+			SET scheduled_export_next_run_at = $3
+			WHERE scheduled_export_id = $1 AND scheduled_export_next_run_at = $2
+			RETURNING scheduled_export_id
+		)
+		INSERT INTO scheduled_export_runs (scheduled_export_run_scheduled_export_id, scheduled_export_run_scheduled_at)
+		SELECT scheduled_export_id, $2
+		FROM scheduled
+		RETURNING scheduled_export_run_id
+	`
+
+	return d.insertScheduledExportRun(ctx, query, job, scheduledExport.ID, scheduledExport.NextRunAt, nextRunAt)
+}
+
+// InsertScheduledExportRun creates a pending run of the scheduled export outside its schedule
+// and queues job with the run ID as key to run it. The run is only created if the job is queued as well.
+func (d *Database) InsertScheduledExportRun(ctx context.Context, scheduledExportID int, scheduledAt time.Time, job Job) (int, error) {
+	query := `
+		INSERT INTO scheduled_export_runs (scheduled_export_run_scheduled_export_id, scheduled_export_run_scheduled_at)
+		VALUES ($1, $2)
+		RETURNING scheduled_export_run_id
+	`
+
+	return d.insertScheduledExportRun(ctx, query, job, scheduledExportID, scheduledAt)
+}
+
+// insertScheduledExportRun runs the query returning the ID of the new run and queues the job for it in the same transaction,
+// so no run stays pending without a job.
+func (d *Database) insertScheduledExportRun(ctx context.Context, query string, job Job, args ...any) (int, error) {
+	tx, err := d.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			slog.ErrorContext(ctx, "failed to rollback transaction", "error", err)
+		}
+	}()
+
+	var id int
+	if err = tx.GetContext(ctx, &id, query, args...); err != nil {
+		return 0, fmt.Errorf("failed to insert scheduled export run: %w", err)
+	}
+
+	job.Key = strconv.Itoa(id)
+	if err = insertJobs(ctx, tx, []Job{job}); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return id, nil
+}
+
+func (d *Database) GetScheduledExportRun(ctx context.Context, id int) (*ScheduledExportRunWithExport, error) {
+	query := `
+		SELECT scheduled_export_runs.*, scheduled_exports.*, export_presets.*
+		FROM scheduled_export_runs
+		JOIN scheduled_exports ON scheduled_export_run_scheduled_export_id = scheduled_export_id
+		JOIN export_presets ON scheduled_export_preset_id = export_preset_id
+		WHERE scheduled_export_run_id = $1
+	`
+
+	var run ScheduledExportRunWithExport
+	if err := d.db.GetContext(ctx, &run, query, id); err != nil {
+		return nil, fmt.Errorf("failed to get scheduled export run: %w", err)
+	}
+
+	return &run, nil
+}
+
+func (d *Database) UpdateScheduledExportRun(ctx context.Context, run ScheduledExportRun) error {
+	query := `
+		UPDATE scheduled_export_runs
+		SET scheduled_export_run_status = :scheduled_export_run_status,
+			scheduled_export_run_attempts = :scheduled_export_run_attempts,
+			scheduled_export_run_events = :scheduled_export_run_events,
+			scheduled_export_run_file_name = :scheduled_export_run_file_name,
+			scheduled_export_run_error = :scheduled_export_run_error,
+			scheduled_export_run_attempted_at = now()
+		WHERE scheduled_export_run_id = :scheduled_export_run_id
+	`
+
+	if _, err := d.db.NamedExecContext(ctx, query, run); err != nil {
+		return fmt.Errorf("failed to update scheduled export run: %w", err)
+	}
+
+	return nil
+}
+
+// GetClubScheduledExportRuns returns the latest runs of all scheduled exports of the club.
+func (d *Database) GetClubScheduledExportRuns(ctx context.Context, clubID string, limit int) ([]ScheduledExportRunWithExport, error) {
+	query := `
+		SELECT scheduled_export_runs.*, scheduled_exports.*, export_presets.*
+		FROM scheduled_export_runs
+		JOIN scheduled_exports ON scheduled_export_run_scheduled_export_id = scheduled_export_id
+		JOIN export_presets ON scheduled_export_preset_id = export_preset_id
+		WHERE scheduled_export_club_id = $1
+		ORDER BY scheduled_export_run_created_at DESC, scheduled_export_run_id DESC
+		LIMIT $2
+	`
+
+	var runs []ScheduledExportRunWithExport
+	if err := d.db.SelectContext(ctx, &runs, query, clubID, limit); err != nil {
+		return nil, fmt.Errorf("failed to get club scheduled export runs: %w", err)
+	}
+
+	return runs, nil
+}
+
+// DeleteScheduledExportRuns deletes finished runs which were created before the given time.
+func (d *Database) DeleteScheduledExportRuns(ctx context.Context, before time.Time) error {
+	query := `
+		DELETE FROM scheduled_export_runs
+		WHERE scheduled_export_run_status != 'pending' AND scheduled_export_run_created_at < $1
+	`
+
+	if _, err := d.db.ExecContext(ctx, query, before); err != nil {
+		return fmt.Errorf("failed to delete scheduled export runs: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,198 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type WebhookTopic string
+
+const (
+	WebhookTopicEventCreated          WebhookTopic = "event.created"
+	WebhookTopicEventUpdated          WebhookTopic = "event.updated"
+	WebhookTopicEventDeleted          WebhookTopic = "event.deleted"
+	WebhookTopicRaffleWinnerConfirmed WebhookTopic = "raffle.winner_confirmed"
+	WebhookTopicRewardCodeRedeemed    WebhookTopic = "reward_code.redeemed"
+)
+
+var AllWebhookTopics = []WebhookTopic{
+	WebhookTopicEventCreated,
+	WebhookTopicEventUpdated,
+	WebhookTopicEventDeleted,
+	WebhookTopicRaffleWinnerConfirmed,
+	WebhookTopicRewardCodeRedeemed,
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusSucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "failed"
+)
+
+type Webhook struct {
+	ID        int            `db:"webhook_id"`
+	ClubID    string         `db:"webhook_club_id"`
+	URL       string         `db:"webhook_url"`
+	Secret    string         `db:"webhook_secret"`
+	Topics    pq.StringArray `db:"webhook_topics"`
+	CreatedBy *string        `db:"webhook_created_by"`
+	CreatedAt time.Time      `db:"webhook_created_at"`
+}
+
+type WebhookDelivery struct {
+	ID             int                   `db:"webhook_delivery_id"`
+	WebhookID      int                   `db:"webhook_delivery_webhook_id"`
+	Topic          WebhookTopic          `db:"webhook_delivery_topic"`
+	Payload        json.RawMessage       `db:"webhook_delivery_payload"`
+	Status         WebhookDeliveryStatus `db:"webhook_delivery_status"`
+	Attempts       int                   `db:"webhook_delivery_attempts"`
+	ResponseStatus *int                  `db:"webhook_delivery_response_status"`
+	Error          string                `db:"webhook_delivery_error"`
+	CreatedAt      time.Time             `db:"webhook_delivery_created_at"`
+	AttemptedAt    *time.Time            `db:"webhook_delivery_attempted_at"`
+}
+
+type WebhookDeliveryWithWebhook struct {
+	WebhookDelivery
+	Webhook
+}
+
+func (d *Database) InsertWebhook(ctx context.Context, webhook Webhook) (int, error) {
+	query := `
+		INSERT INTO webhooks (webhook_club_id, webhook_url, webhook_secret, webhook_topics, webhook_created_by)
+		VALUES (:webhook_club_id, :webhook_url, :webhook_secret, :webhook_topics, :webhook_created_by)
+		RETURNING webhook_id
+	`
+
+	query, args, err := d.db.BindNamed(query, webhook)
+	if err != nil {
+		return 0, fmt.Errorf("failed to bind named query: %w", err)
+	}
+
+	var id int
+	if err = d.db.GetContext(ctx, &id, query, args...); err != nil {
+		return 0, fmt.Errorf("failed to insert webhook: %w", err)
+	}
+
+	return id, nil
+}
+
+func (d *Database) GetClubWebhooks(ctx context.Context, clubID string) ([]Webhook, error) {
+	query := `
+		SELECT *
+		FROM webhooks
+		WHERE webhook_club_id = $1
+		ORDER BY webhook_created_at DESC
+	`
+
+	var webhooks []Webhook
+	if err := d.db.SelectContext(ctx, &webhooks, query, clubID); err != nil {
+		return nil, fmt.Errorf("failed to get club webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+func (d *Database) DeleteWebhook(ctx context.Context, id int, clubID string) error {
+	query := `
+		DELETE FROM webhooks
+		WHERE webhook_id = $1 AND webhook_club_id = $2
+	`
+
+	if _, err := d.db.ExecContext(ctx, query, id, clubID); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	return nil
+}
+
+// InsertWebhookDeliveries creates a pending delivery of the payload for every webhook of the club subscribed to the topic and returns their IDs.
+func (d *Database) InsertWebhookDeliveries(ctx context.Context, clubID string, topic WebhookTopic, payload json.RawMessage) ([]int, error) {
+	query := `
+		INSERT INTO webhook_deliveries (webhook_delivery_webhook_id, webhook_delivery_topic, webhook_delivery_payload)
+		SELECT webhook_id, $2, $3
+		FROM webhooks
+		WHERE webhook_club_id = $1 AND $2 = ANY(webhook_topics)
+		RETURNING webhook_delivery_id
+	`
+
+	var ids []int
+	if err := d.db.SelectContext(ctx, &ids, query, clubID, topic, payload); err != nil {
+		return nil, fmt.Errorf("failed to insert webhook deliveries: %w", err)
+	}
+
+	return ids, nil
+}
+
+func (d *Database) GetWebhookDelivery(ctx context.Context, id int) (*WebhookDeliveryWithWebhook, error) {
+	query := `
+		SELECT webhook_deliveries.*, webhooks.*
+		FROM webhook_deliveries
+		JOIN webhooks ON webhook_delivery_webhook_id = webhook_id
+		WHERE webhook_delivery_id = $1
+	`
+
+	var delivery WebhookDeliveryWithWebhook
+	if err := d.db.GetContext(ctx, &delivery, query, id); err != nil {
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+
+	return &delivery, nil
+}
+
+func (d *Database) UpdateWebhookDelivery(ctx context.Context, delivery WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET webhook_delivery_status = :webhook_delivery_status,
+			webhook_delivery_attempts = :webhook_delivery_attempts,
+			webhook_delivery_response_status = :webhook_delivery_response_status,
+			webhook_delivery_error = :webhook_delivery_error,
+			webhook_delivery_attempted_at = now()
+		WHERE webhook_delivery_id = :webhook_delivery_id
+	`
+
+	if _, err := d.db.NamedExecContext(ctx, query, delivery); err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// GetClubWebhookDeliveries returns the latest deliveries of all webhooks of the club.
+func (d *Database) GetClubWebhookDeliveries(ctx context.Context, clubID string, limit int) ([]WebhookDeliveryWithWebhook, error) {
+	query := `
+		SELECT webhook_deliveries.*, webhooks.*
+		FROM webhook_deliveries
+		JOIN webhooks ON webhook_delivery_webhook_id = webhook_id
+		WHERE webhook_club_id = $1
+		ORDER BY webhook_delivery_created_at DESC, webhook_delivery_id DESC
+		LIMIT $2
+	`
+
+	var deliveries []WebhookDeliveryWithWebhook
+	if err := d.db.SelectContext(ctx, &deliveries, query, clubID, limit); err != nil {
+		return nil, fmt.Errorf("failed to get club webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// DeleteWebhookDeliveries deletes finished deliveries which were created before the given time.
+func (d *Database) DeleteWebhookDeliveries(ctx context.Context, before time.Time) error {
+	query := `
+		DELETE FROM webhook_deliveries
+		WHERE webhook_delivery_status != 'pending' AND webhook_delivery_created_at < $1
+	`
+
+	if _, err := d.db.ExecContext(ctx, query, before); err != nil {
+		return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+	}
+
+	return nil
+}
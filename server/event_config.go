@@ -0,0 +1,79 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/topi314/campfire-tools/server/database"
+)
+
+// eventConfigTTL is how long the event config is cached. Changes made on other instances show up after it expired.
+const eventConfigTTL = time.Minute
+
+// EventConfig is the event categories, digital code exclude patterns and configured events managed on the admin page.
+type EventConfig struct {
+	Categories                 []database.EventCategory
+	DigitalCodeExcludePatterns []string
+	ConfiguredEvents           []database.ConfiguredEvent
+}
+
+func newEventConfigCache(db *database.Database) *EventConfigCache {
+	return &EventConfigCache{
+		db: db,
+	}
+}
+
+// EventConfigCache caches the EventConfig, since categorizing events is needed by most pages.
+type EventConfigCache struct {
+	db       *database.Database
+	mu       sync.Mutex
+	config   EventConfig
+	loadedAt time.Time
+}
+
+// Get returns the cached event config, loading it from the database if it expired.
+func (c *EventConfigCache) Get(ctx context.Context) (EventConfig, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.loadedAt.IsZero() && time.Since(c.loadedAt) < eventConfigTTL {
+		return c.config, nil
+	}
+
+	categories, err := c.db.GetEventCategories(ctx)
+	if err != nil {
+		return EventConfig{}, fmt.Errorf("failed to load event categories: %w", err)
+	}
+
+	excludePatterns, err := c.db.GetDigitalCodeExcludePatterns(ctx)
+	if err != nil {
+		return EventConfig{}, fmt.Errorf("failed to load digital code exclude patterns: %w", err)
+	}
+	patterns := make([]string, len(excludePatterns))
+	for i, pattern := range excludePatterns {
+		patterns[i] = pattern.Pattern
+	}
+
+	configuredEvents, err := c.db.GetConfiguredEvents(ctx)
+	if err != nil {
+		return EventConfig{}, fmt.Errorf("failed to load configured events: %w", err)
+	}
+
+	c.config = EventConfig{
+		Categories:                 categories,
+		DigitalCodeExcludePatterns: patterns,
+		ConfiguredEvents:           configuredEvents,
+	}
+	c.loadedAt = time.Now()
+	return c.config, nil
+}
+
+// Invalidate makes the next Get load the event config from the database again.
+func (c *EventConfigCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.loadedAt = time.Time{}
+}
@@ -2,8 +2,6 @@ package server
 
 import (
 	"context"
-	"database/sql"
-	"errors"
 	"fmt"
 	"log/slog"
 	"slices"
@@ -13,37 +11,15 @@ import (
 	"github.com/topi314/campfire-tools/server/database"
 )
 
-func (s *Server) importEvents() {
-	for {
-		s.doImportEvents()
-		time.Sleep(10 * time.Second)
-	}
-}
+func (s *Server) runImportClubEventsJob(ctx context.Context, job database.Job) error {
+	clubID := job.Key
 
-func (s *Server) doImportEvents() {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
-	defer cancel()
+	slog.InfoContext(ctx, "Importing events for club", slog.String("club_id", clubID))
 
-	if err := s.doImportNextEvent(ctx); err != nil {
-		slog.ErrorContext(ctx, "Failed to import next event", slog.Any("err", err))
-	}
-}
+	importErr := s.importActiveClubEvents(ctx, clubID)
 
-func (s *Server) doImportNextEvent(ctx context.Context) error {
-	club, err := s.DB.GetNextClubImport(ctx)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil
-		}
-		return err
-	}
-
-	slog.InfoContext(ctx, "Importing events for club", slog.String("club_id", club.ID), slog.String("club_name", club.Name))
-
-	importErr := s.importActiveClubEvents(ctx, club.ID)
-
-	if err = s.DB.UpdateClubLastAutoEventImported(ctx, club.ID); err != nil {
-		slog.ErrorContext(ctx, "Failed to update club last auto event import", slog.String("club_id", club.ID), slog.Any("err", err))
+	if err := s.DB.UpdateClubLastAutoEventImported(ctx, clubID); err != nil {
+		slog.ErrorContext(ctx, "Failed to update club last auto event import", slog.String("club_id", clubID), slog.Any("err", err))
 	}
 
 	return importErr
@@ -83,6 +59,10 @@ func (s *Server) importActiveClubEvents(ctx context.Context, clubID string) erro
 			slog.Int("rsvps", len(event.RSVPStatuses)),
 			slog.Int("members", len(members)),
 		)
+
+		if err = s.PublishWebhook(ctx, clubID, database.WebhookTopicEventCreated, newWebhookEvent(event, clubID)); err != nil {
+			slog.ErrorContext(ctx, "Failed to publish event created webhook", slog.String("club_id", clubID), slog.String("event_id", event.ID), slog.Any("err", err))
+		}
 	}
 
 	return nil
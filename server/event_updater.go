@@ -2,45 +2,22 @@ package server
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"log/slog"
-	"time"
 
 	"github.com/topi314/campfire-tools/server/campfire"
+	"github.com/topi314/campfire-tools/server/database"
 )
 
-func (s *Server) updateEvents() {
-	for {
-		s.doUpdateEvents()
-		time.Sleep(10 * time.Second)
-	}
-}
-
-func (s *Server) doUpdateEvents() {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
-	defer cancel()
-
-	if err := s.doUpdateNextEvent(ctx); err != nil {
-		slog.ErrorContext(ctx, "Failed to update next event", slog.Any("err", err))
-	}
-}
-
-func (s *Server) doUpdateNextEvent(ctx context.Context) error {
-	event, err := s.DB.GetNextUpdateEvent(ctx)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil
-		}
-		return err
-	}
+func (s *Server) runUpdateEventJob(ctx context.Context, job database.Job) error {
+	eventID := job.Key
 
-	slog.InfoContext(ctx, "Updating event", slog.String("club_id", event.ClubID), slog.String("event_id", event.ID), slog.String("event_name", event.Name))
+	slog.InfoContext(ctx, "Updating event", slog.String("event_id", eventID))
 
-	importErr := s.importEvent(ctx, event.ID)
+	importErr := s.importEvent(ctx, eventID)
 
-	if err = s.DB.UpdateEventLastAutoImported(ctx, event.ID); err != nil {
-		slog.ErrorContext(ctx, "Failed to update event last auto import", slog.String("event_id", event.ID), slog.Any("err", err))
+	if err := s.DB.UpdateEventLastAutoImported(ctx, eventID); err != nil {
+		slog.ErrorContext(ctx, "Failed to update event last auto import", slog.String("event_id", eventID), slog.Any("err", err))
 	}
 
 	return importErr
@@ -50,18 +27,25 @@ func (s *Server) importEvent(ctx context.Context, eventID string) error {
 	event, err := s.Campfire.GetEvent(ctx, eventID)
 	if err != nil {
 		if errors.Is(err, campfire.ErrEventNotFound) {
-			if err = s.DB.DeleteEvent(ctx, eventID); err != nil {
-				slog.ErrorContext(ctx, "Failed to delete not found event", slog.String("event_id", eventID), slog.Any("err", err))
-			}
+			s.deleteNotFoundEvent(ctx, eventID)
 			return nil
 		}
 		return err
 	}
 
+	oldRSVPs, err := s.DB.GetEventRSVPs(ctx, eventID)
+	if err != nil {
+		return err
+	}
+
 	if err = s.ProcessFullEventImport(ctx, *event, true); err != nil {
 		return err
 	}
 
+	if rsvpsChanged(oldRSVPs, event.RSVPStatuses) {
+		s.publishEventUpdated(ctx, *event)
+	}
+
 	slog.InfoContext(ctx, "Updated event",
 		slog.String("club_id", event.Club.ID),
 		slog.String("event_id", event.ID),
@@ -72,3 +56,78 @@ func (s *Server) importEvent(ctx context.Context, eventID string) error {
 
 	return nil
 }
+
+// publishEventUpdated publishes the event.updated webhook to the club the event is stored with.
+// Events without a stored club are skipped.
+func (s *Server) publishEventUpdated(ctx context.Context, event campfire.Event) {
+	storedEvent, err := s.DB.GetEvent(ctx, event.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get updated event", slog.String("event_id", event.ID), slog.Any("err", err))
+		return
+	}
+	if storedEvent.ClubID == "" {
+		return
+	}
+
+	if err = s.PublishWebhook(ctx, storedEvent.ClubID, database.WebhookTopicEventUpdated, newWebhookEvent(event, storedEvent.ClubID)); err != nil {
+		slog.ErrorContext(ctx, "Failed to publish event updated webhook", slog.String("event_id", event.ID), slog.Any("err", err))
+	}
+}
+
+func (s *Server) deleteNotFoundEvent(ctx context.Context, eventID string) {
+	event, err := s.DB.GetEvent(ctx, eventID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get not found event", slog.String("event_id", eventID), slog.Any("err", err))
+		return
+	}
+
+	rsvps, err := s.DB.GetEventRSVPs(ctx, eventID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get not found event RSVPs", slog.String("event_id", eventID), slog.Any("err", err))
+		return
+	}
+
+	if err = s.DB.DeleteEvent(ctx, eventID); err != nil {
+		slog.ErrorContext(ctx, "Failed to delete not found event", slog.String("event_id", eventID), slog.Any("err", err))
+		return
+	}
+
+	statuses := make([]string, len(rsvps))
+	for i, rsvp := range rsvps {
+		statuses[i] = rsvp.Status
+	}
+	accepted, checkedIn := countRSVPs(statuses)
+
+	if event.ClubID == "" {
+		return
+	}
+
+	if err = s.PublishWebhook(ctx, event.ClubID, database.WebhookTopicEventDeleted, WebhookEvent{
+		ID:                           event.Event.ID,
+		Name:                         event.Name,
+		URL:                          campfire.EventURL(event.Event.ID),
+		ClubID:                       event.ClubID,
+		Time:                         event.Time,
+		EndTime:                      event.EndTime,
+		CreatedByCommunityAmbassador: event.Event.CreatedByCommunityAmbassador,
+		Accepted:                     accepted,
+		CheckedIn:                    checkedIn,
+	}); err != nil {
+		slog.ErrorContext(ctx, "Failed to publish event deleted webhook", slog.String("event_id", eventID), slog.Any("err", err))
+	}
+}
+
+// rsvpsChanged reports whether the RSVPs fetched from Campfire differ from the stored ones.
+// Stored RSVPs missing from Campfire are ignored, the import never deletes them, so they would be reported on every update.
+func rsvpsChanged(stored []database.EventRSVP, fetched []campfire.RSVPStatus) bool {
+	statuses := make(map[string]string, len(stored))
+	for _, rsvp := range stored {
+		statuses[rsvp.MemberID] = rsvp.Status
+	}
+	for _, rsvp := range fetched {
+		if status, ok := statuses[rsvp.UserID]; !ok || status != rsvp.RSVPStatus {
+			return true
+		}
+	}
+	return false
+}
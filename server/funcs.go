@@ -32,6 +32,7 @@ var templateFuncs = template.FuncMap{
 	"formatTimeToDayTime":    formatTimeToDayTime,
 	"formatDayTime":          formatDayTime,
 	"formatTimeToRelDayTime": formatTimeToRelDayTime,
+	"weekdayName":            weekdayName,
 }
 
 func add(a, b any) (int, error) {
@@ -238,3 +239,7 @@ func dict(a ...any) (map[string]any, error) {
 	}
 	return m, nil
 }
+
+func weekdayName(day int) string {
+	return time.Weekday(day).String()
+}
@@ -0,0 +1,232 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/topi314/campfire-tools/server/database"
+)
+
+// ErrContinueLater can be returned by job handlers which made progress but ran out of time.
+// The job is queued again right away without counting as a failed attempt.
+var ErrContinueLater = errors.New("continue later")
+
+// failedJobRetention is how long failed jobs are kept to be looked at and retried.
+const failedJobRetention = 7 * 24 * time.Hour
+
+type jobHandler func(ctx context.Context, job database.Job) error
+
+// jobPriorities defines the priority of each job kind, higher runs first.
+var jobPriorities = map[database.JobKind]int{
+	database.JobKindImportClub:         20,
+	database.JobKindImportClubEvents:   10,
+	database.JobKindUpdateEvent:        0,
+	database.JobKindSendClubDigest:     5,
+	database.JobKindDeliverWebhook:     15,
+	database.JobKindRunScheduledExport: 5,
+}
+
+func (s *Server) jobHandler(kind database.JobKind) (jobHandler, bool) {
+	switch kind {
+	case database.JobKindImportClub:
+		return s.runImportClubJob, true
+	case database.JobKindImportClubEvents:
+		return s.runImportClubEventsJob, true
+	case database.JobKindUpdateEvent:
+		return s.runUpdateEventJob, true
+	case database.JobKindSendClubDigest:
+		return s.runSendClubDigestJob, true
+	case database.JobKindDeliverWebhook:
+		return s.runDeliverWebhookJob, true
+	case database.JobKindRunScheduledExport:
+		return s.runScheduledExportJob, true
+	default:
+		return nil, false
+	}
+}
+
+// NewJob creates a job of the given kind with the configured defaults.
+func (s *Server) NewJob(kind database.JobKind, key string) database.Job {
+	return database.Job{
+		Kind:        kind,
+		Key:         key,
+		Priority:    jobPriorities[kind],
+		MaxAttempts: s.Cfg.Jobs.MaxAttempts,
+	}
+}
+
+func (s *Server) runJobs() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	requeued, err := s.DB.RequeueRunningJobs(ctx)
+	cancel()
+	if err != nil {
+		slog.Error("Failed to requeue running jobs", slog.Any("err", err))
+	} else if requeued > 0 {
+		slog.Info("Requeued interrupted jobs", slog.Int("jobs", requeued))
+	}
+
+	go s.scheduleJobs()
+	for i := range max(s.Cfg.Jobs.Workers, 1) {
+		go s.runJobWorker(i)
+	}
+}
+
+func (s *Server) scheduleJobs() {
+	for {
+		s.doScheduleJobs()
+		time.Sleep(time.Duration(s.Cfg.Jobs.PollInterval))
+	}
+}
+
+// doScheduleJobs queues jobs for all work which is currently due. Work which already has an active or cancelled job is skipped by the database.
+func (s *Server) doScheduleJobs() {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	var jobs []database.Job
+
+	clubImportJobs, err := s.DB.GetPendingClubImportJobs(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get pending club import jobs", slog.Any("err", err))
+	}
+	for _, job := range clubImportJobs {
+		jobs = append(jobs, s.NewJob(database.JobKindImportClub, strconv.Itoa(job.ID)))
+	}
+
+	clubs, err := s.DB.GetClubsDueForEventImport(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get clubs due for event import", slog.Any("err", err))
+	}
+	for _, club := range clubs {
+		jobs = append(jobs, s.NewJob(database.JobKindImportClubEvents, club.ID))
+	}
+
+	events, err := s.DB.GetEventsDueForUpdate(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get events due for update", slog.Any("err", err))
+	}
+	for _, event := range events {
+		jobs = append(jobs, s.NewJob(database.JobKindUpdateEvent, event.ID))
+	}
+
+	digestClubs, err := s.DB.GetClubsWithDigests(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get clubs with digests", slog.Any("err", err))
+	}
+	jobs = append(jobs, s.clubDigestJobs(digestClubs, time.Now())...)
+
+	scheduledExports, err := s.DB.GetDueScheduledExports(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get due scheduled exports", slog.Any("err", err))
+	}
+	s.scheduleExportRuns(ctx, scheduledExports, time.Now())
+
+	if err = s.DB.InsertJobs(ctx, jobs); err != nil {
+		slog.ErrorContext(ctx, "Failed to queue jobs", slog.Any("err", err))
+	}
+
+	if err = s.DB.DeleteFinishedJobs(ctx, time.Now().Add(-24*time.Hour)); err != nil {
+		slog.ErrorContext(ctx, "Failed to delete finished jobs", slog.Any("err", err))
+	}
+
+	if err = s.DB.DeleteFailedJobs(ctx, time.Now().Add(-failedJobRetention)); err != nil {
+		slog.ErrorContext(ctx, "Failed to delete failed jobs", slog.Any("err", err))
+	}
+
+	if err = s.DB.DeleteWebhookDeliveries(ctx, time.Now().Add(-webhookDeliveryRetention)); err != nil {
+		slog.ErrorContext(ctx, "Failed to delete webhook deliveries", slog.Any("err", err))
+	}
+
+	if err = s.DB.DeleteScheduledExportRuns(ctx, time.Now().Add(-scheduledExportRunRetention)); err != nil {
+		slog.ErrorContext(ctx, "Failed to delete scheduled export runs", slog.Any("err", err))
+	}
+}
+
+func (s *Server) runJobWorker(worker int) {
+	for {
+		if !s.doRunNextJob(worker) {
+			time.Sleep(time.Duration(s.Cfg.Jobs.PollInterval))
+		}
+	}
+}
+
+// doRunNextJob claims and runs the next due job. It returns false if there was no job to run.
+func (s *Server) doRunNextJob(worker int) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(s.Cfg.Jobs.Timeout))
+	defer cancel()
+
+	job, err := s.DB.ClaimNextJob(ctx)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			slog.ErrorContext(ctx, "Failed to claim next job", slog.Int("worker", worker), slog.Any("err", err))
+		}
+		return false
+	}
+
+	logArgs := []any{
+		slog.Int("worker", worker),
+		slog.Int("job_id", job.ID),
+		slog.String("job_kind", string(job.Kind)),
+		slog.String("job_key", job.Key),
+		slog.Int("attempt", job.Attempts),
+	}
+	slog.DebugContext(ctx, "Running job", logArgs...)
+
+	runErr := s.runJob(ctx, *job)
+	switch {
+	case runErr == nil:
+		job.Status = database.JobStatusCompleted
+		job.LastError = ""
+	case errors.Is(runErr, ErrContinueLater):
+		job.Status = database.JobStatusQueued
+		job.Attempts--
+		job.NextRunAt = time.Now()
+	case job.Attempts >= job.MaxAttempts:
+		job.Status = database.JobStatusFailed
+		job.LastError = runErr.Error()
+		slog.ErrorContext(ctx, "Job failed", append(logArgs, slog.Any("err", runErr))...)
+	default:
+		job.Status = database.JobStatusQueued
+		job.LastError = runErr.Error()
+		job.NextRunAt = time.Now().Add(s.Cfg.Jobs.backoff(job.Attempts))
+		slog.WarnContext(ctx, "Job failed, retrying later", append(logArgs, slog.Time("next_run_at", job.NextRunAt), slog.Any("err", runErr))...)
+	}
+
+	if err = s.DB.UpdateJob(context.WithoutCancel(ctx), *job); err != nil {
+		slog.ErrorContext(ctx, "Failed to update job", append(logArgs, slog.Any("err", err))...)
+	}
+
+	return true
+}
+
+func (s *Server) runJob(ctx context.Context, job database.Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	handler, ok := s.jobHandler(job.Kind)
+	if !ok {
+		return fmt.Errorf("unknown job kind: %s", job.Kind)
+	}
+
+	return handler(ctx, job)
+}
+
+// backoff returns the delay before the next attempt, doubling with every failed attempt.
+func (c JobsConfig) backoff(attempts int) time.Duration {
+	delay := time.Duration(c.BackoffBase)
+	for range attempts - 1 {
+		delay *= 2
+		if delay >= time.Duration(c.BackoffMax) {
+			return time.Duration(c.BackoffMax)
+		}
+	}
+	return min(delay, time.Duration(c.BackoffMax))
+}
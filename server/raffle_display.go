@@ -0,0 +1,68 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"sync"
+)
+
+// RaffleDisplayToken returns the signed token which grants read-only access to the live display of a raffle.
+func (s *Server) RaffleDisplayToken(raffleID int) string {
+	mac := hmac.New(sha256.New, []byte(s.Cfg.Server.SigningSecret))
+	mac.Write([]byte("raffle-display:" + strconv.Itoa(raffleID)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (s *Server) VerifyRaffleDisplayToken(raffleID int, token string) bool {
+	return hmac.Equal([]byte(s.RaffleDisplayToken(raffleID)), []byte(token))
+}
+
+func newRaffleUpdates() *RaffleUpdates {
+	return &RaffleUpdates{
+		subscribers: make(map[int]map[chan struct{}]struct{}),
+	}
+}
+
+// RaffleUpdates notifies live raffle displays when the winners of a raffle change.
+// Notifications are coalesced, subscribers are expected to reload the whole raffle state.
+type RaffleUpdates struct {
+	mu          sync.Mutex
+	subscribers map[int]map[chan struct{}]struct{}
+}
+
+// Subscribe returns a channel receiving updates of the raffle and a function to unsubscribe again.
+func (u *RaffleUpdates) Subscribe(raffleID int) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.subscribers[raffleID] == nil {
+		u.subscribers[raffleID] = make(map[chan struct{}]struct{})
+	}
+	u.subscribers[raffleID][ch] = struct{}{}
+
+	return ch, func() {
+		u.mu.Lock()
+		defer u.mu.Unlock()
+
+		delete(u.subscribers[raffleID], ch)
+		if len(u.subscribers[raffleID]) == 0 {
+			delete(u.subscribers, raffleID)
+		}
+	}
+}
+
+func (u *RaffleUpdates) Publish(raffleID int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	for ch := range u.subscribers[raffleID] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/disgo/webhook"
+
+	"github.com/topi314/campfire-tools/internal/cron"
+	"github.com/topi314/campfire-tools/server/database"
+)
+
+const scheduledExportRunRetention = 90 * 24 * time.Hour
+
+// ExportFile describes the file written by a PresetExporter.
+type ExportFile struct {
+	Extension   string
+	ContentType string
+	Events      int
+}
+
+// PresetExporter writes the export of a preset with the events of its event range at the given time to w.
+// Nothing is written if no event matches. The exporter is provided by the tracker, which owns the export formats.
+type PresetExporter func(ctx context.Context, preset database.ExportPreset, now time.Time, w io.Writer) (ExportFile, error)
+
+// NextScheduledExportRun returns the first time after the given time the cron schedule is due. Schedules are evaluated in UTC.
+func NextScheduledExportRun(schedule string, after time.Time) (time.Time, error) {
+	s, err := cron.Parse(schedule)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid schedule: %w", err)
+	}
+
+	next := s.Next(after.UTC())
+	if next.IsZero() {
+		return time.Time{}, errors.New("invalid schedule: it never runs")
+	}
+	return next, nil
+}
+
+// scheduleExportRuns creates a run for every due scheduled export together with the job to run it.
+// Runs missed while the server was down are caught up with a single run.
+func (s *Server) scheduleExportRuns(ctx context.Context, scheduledExports []database.ScheduledExport, now time.Time) {
+	for _, scheduledExport := range scheduledExports {
+		nextRunAt, err := NextScheduledExportRun(scheduledExport.Schedule, now)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to get next scheduled export run", slog.Int("scheduled_export_id", scheduledExport.ID), slog.Any("err", err))
+			continue
+		}
+
+		if _, err = s.DB.ScheduleExportRun(ctx, scheduledExport, nextRunAt, s.NewJob(database.JobKindRunScheduledExport, "")); err != nil && !errors.Is(err, sql.ErrNoRows) {
+			slog.ErrorContext(ctx, "Failed to schedule export run", slog.Int("scheduled_export_id", scheduledExport.ID), slog.Any("err", err))
+		}
+	}
+}
+
+// RunScheduledExport queues a run of the scheduled export right away, independent of its schedule.
+func (s *Server) RunScheduledExport(ctx context.Context, scheduledExport database.ScheduledExport) error {
+	_, err := s.DB.InsertScheduledExportRun(ctx, scheduledExport.ID, time.Now().UTC(), s.NewJob(database.JobKindRunScheduledExport, ""))
+	return err
+}
+
+func (s *Server) runScheduledExportJob(ctx context.Context, job database.Job) error {
+	runID, err := strconv.Atoi(job.Key)
+	if err != nil {
+		return fmt.Errorf("invalid scheduled export run ID: %w", err)
+	}
+
+	run, err := s.DB.GetScheduledExportRun(ctx, runID)
+	if err != nil {
+		// the scheduled export was deleted together with its runs
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	fileName, events, runErr := s.runScheduledExport(ctx, *run)
+
+	run.Attempts = job.Attempts
+	run.Events = events
+	run.FileName = fileName
+	switch {
+	case runErr == nil:
+		run.Status = database.ScheduledExportRunStatusSucceeded
+		run.Error = ""
+	case job.Attempts >= job.MaxAttempts:
+		run.Status = database.ScheduledExportRunStatusFailed
+		run.Error = runErr.Error()
+	default:
+		run.Status = database.ScheduledExportRunStatusPending
+		run.Error = runErr.Error()
+	}
+
+	if err = s.DB.UpdateScheduledExportRun(context.WithoutCancel(ctx), run.ScheduledExportRun); err != nil {
+		slog.ErrorContext(ctx, "Failed to update scheduled export run", slog.Int("run_id", runID), slog.Any("err", err))
+	}
+
+	return runErr
+}
+
+// runScheduledExport exports the preset of the run into a temporary file and delivers it to the destination.
+// It returns the name of the delivered file and the number of exported events. Exports without events are not delivered.
+func (s *Server) runScheduledExport(ctx context.Context, run database.ScheduledExportRunWithExport) (string, int, error) {
+	if s.PresetExporter == nil {
+		return "", 0, errors.New("no preset exporter configured")
+	}
+
+	f, err := os.CreateTemp("", "campfire-tools-export-*")
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create temporary export file: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}()
+
+	exportFile, err := s.PresetExporter(ctx, run.ExportPreset, run.ScheduledAt, f)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to export events: %w", err)
+	}
+	if exportFile.Events == 0 {
+		return "", 0, nil
+	}
+
+	if _, err = f.Seek(0, io.SeekStart); err != nil {
+		return "", 0, fmt.Errorf("failed to rewind export file: %w", err)
+	}
+
+	fileName := scheduledExportFileName(run.ExportPreset.Name, run.ScheduledAt, exportFile.Extension)
+	switch run.Destination {
+	case database.ScheduledExportDestinationDiscord:
+		err = s.sendScheduledExportToDiscord(ctx, run, fileName, exportFile.Events, f)
+	case database.ScheduledExportDestinationS3:
+		err = s.uploadScheduledExportToS3(ctx, run, fileName, exportFile.ContentType, f)
+	default:
+		err = fmt.Errorf("invalid scheduled export destination: %s", run.Destination)
+	}
+	if err != nil {
+		return "", exportFile.Events, err
+	}
+
+	slog.InfoContext(ctx, "Delivered scheduled export",
+		slog.Int("scheduled_export_id", run.ScheduledExport.ID),
+		slog.String("destination", string(run.Destination)),
+		slog.String("file_name", fileName),
+		slog.Int("events", exportFile.Events),
+	)
+	return fileName, exportFile.Events, nil
+}
+
+// sendScheduledExportToDiscord uploads the export to the Discord webhook of the scheduled export.
+// The notification webhook of the server is never used, it posts to the channel of the operator.
+func (s *Server) sendScheduledExportToDiscord(ctx context.Context, run database.ScheduledExportRunWithExport, fileName string, events int, r io.Reader) error {
+	if run.Target == "" {
+		return errors.New("no Discord webhook URL configured")
+	}
+
+	client, err := webhook.NewWithURL(run.Target)
+	if err != nil {
+		return fmt.Errorf("invalid scheduled export webhook URL: %w", err)
+	}
+	defer client.Close(context.WithoutCancel(ctx))
+
+	club, err := s.DB.GetClub(ctx, run.ScheduledExport.ClubID)
+	if err != nil {
+		return fmt.Errorf("failed to get club: %w", err)
+	}
+
+	if _, err = client.CreateMessage(discord.WebhookMessageCreate{
+		Content: fmt.Sprintf("**%s**: %s export with %d events", club.Name, run.ExportPreset.Name, events),
+		Files:   []*discord.File{discord.NewFile(fileName, "", r)},
+	}, rest.CreateWebhookMessageParams{
+		Wait: true,
+	}, rest.WithCtx(ctx)); err != nil {
+		return fmt.Errorf("failed to send scheduled export: %w", err)
+	}
+
+	return nil
+}
+
+// uploadScheduledExportToS3 uploads the export to the configured bucket, below the key prefix of the scheduled export.
+// The key prefix must be below the folder of the club of the scheduled export.
+func (s *Server) uploadScheduledExportToS3(ctx context.Context, run database.ScheduledExportRunWithExport, fileName string, contentType string, r io.ReadSeeker) error {
+	if s.S3 == nil {
+		return errors.New("no S3 storage configured")
+	}
+	if clubID := run.ScheduledExport.ClubID; run.Target != clubID && !strings.HasPrefix(run.Target, clubID+"/") {
+		return fmt.Errorf("scheduled export key prefix %q is not below the club folder %q", run.Target, clubID)
+	}
+
+	if err := s.S3.PutObject(ctx, path.Join(run.Target, fileName), r, contentType); err != nil {
+		return fmt.Errorf("failed to upload scheduled export: %w", err)
+	}
+
+	return nil
+}
+
+// scheduledExportFileName returns the file name of an export, made up of the preset name and the time it was scheduled for.
+func scheduledExportFileName(presetName string, scheduledAt time.Time, extension string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		case r == ' ' || r == '.':
+			return '_'
+		}
+		return -1
+	}, strings.TrimSpace(presetName))
+	if name == "" {
+		name = "export"
+	}
+
+	return fmt.Sprintf("%s_%s.%s", name, scheduledAt.UTC().Format("2006-01-02_1504"), extension)
+}
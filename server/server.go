@@ -3,7 +3,6 @@ package server
 import (
 	"bytes"
 	"context"
-	"database/sql"
 	"embed"
 	"errors"
 	"fmt"
@@ -22,6 +21,8 @@ import (
 	"github.com/disgoorg/disgo/webhook"
 	"github.com/topi314/goreload"
 
+	"github.com/topi314/campfire-tools/internal/s3"
+	"github.com/topi314/campfire-tools/internal/xrand"
 	"github.com/topi314/campfire-tools/server/auth"
 	"github.com/topi314/campfire-tools/server/campfire"
 	"github.com/topi314/campfire-tools/server/cauth"
@@ -100,6 +101,11 @@ func New(cfg Config) (*Server, error) {
 		slog.Info("Discord webhook notifications enabled", slog.String("name", wh.Name()), slog.String("guild_id", wh.GuildID.String()), slog.String("channel_id", wh.ChannelID.String()))
 	}
 
+	if cfg.Server.SigningSecret == "" {
+		slog.Warn("No signing secret configured, signed links like raffle displays will stop working after a restart")
+		cfg.Server.SigningSecret = xrand.NewSeed()
+	}
+
 	logoPNG, err := png.Decode(bytes.NewReader(logo))
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode logo: %w", err)
@@ -107,6 +113,15 @@ func New(cfg Config) (*Server, error) {
 
 	httpClient := &http.Client{}
 
+	var s3Client *s3.Client
+	if cfg.S3.Enabled {
+		s3Client, err = s3.New(cfg.S3, httpClient)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create s3 client: %w", err)
+		}
+		slog.Info("S3 export storage enabled", slog.String("endpoint", cfg.S3.Endpoint), slog.String("bucket", cfg.S3.Bucket))
+	}
+
 	campfireHTTPClient := &http.Client{
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			return http.ErrUseLastResponse
@@ -120,16 +135,21 @@ func New(cfg Config) (*Server, error) {
 		RewardsServer: &http.Server{
 			Addr: cfg.Server.RewardsAddr,
 		},
-		HttpClient:    httpClient,
-		Campfire:      campfire.New(cfg.Campfire, campfireHTTPClient, getCampfireToken(db)),
-		DB:            db,
-		Auth:          auth.New(cfg.DiscordAuth, cfg.Server.PublicTrackerURL),
-		CampfireAuth:  cauth.New(cfg.CampfireAuth),
-		Templates:     t,
-		StaticFS:      staticFS,
-		WebhookClient: webhookClient,
-		Reloader:      reloader,
-		Logo:          logoPNG,
+		HttpClient:        httpClient,
+		WebhookHttpClient: newWebhookHttpClient(),
+		Campfire:          campfire.New(cfg.Campfire, campfireHTTPClient, getCampfireTokens(db)),
+		DB:                db,
+		Auth:              auth.New(cfg.DiscordAuth, cfg.Server.PublicTrackerURL),
+		CampfireAuth:      cauth.New(cfg.CampfireAuth),
+		Templates:         t,
+		StaticFS:          staticFS,
+		WebhookClient:     webhookClient,
+		S3:                s3Client,
+		Reloader:          reloader,
+		Logo:              logoPNG,
+		RaffleUpdates:     newRaffleUpdates(),
+		APIKeyLimiter:     newAPIKeyLimiter(),
+		EventConfig:       newEventConfigCache(db),
 	}
 
 	go s.cleanup()
@@ -137,17 +157,22 @@ func New(cfg Config) (*Server, error) {
 	return s, nil
 }
 
-func getCampfireToken(db *database.Database) func(ctx context.Context) (string, error) {
-	return func(ctx context.Context) (string, error) {
-		token, err := db.GetNextCampfireToken(ctx)
+func getCampfireTokens(db *database.Database) campfire.TokensFunc {
+	return func(ctx context.Context) ([]campfire.Token, error) {
+		tokens, err := db.GetValidCampfireTokens(ctx)
 		if err != nil {
-			if errors.Is(err, sql.ErrNoRows) {
-				return "", errors.New("oooops, no valid token found. Please ping me on Discord with this error")
-			}
-			return "", fmt.Errorf("failed to get next campfire token: %w", err)
+			return nil, fmt.Errorf("failed to get valid campfire tokens: %w", err)
 		}
 
-		return token.Token, nil
+		campfireTokens := make([]campfire.Token, len(tokens))
+		for i, token := range tokens {
+			campfireTokens[i] = campfire.Token{
+				ID:        token.ID,
+				Token:     token.Token,
+				ExpiresAt: token.ExpiresAt,
+			}
+		}
+		return campfireTokens, nil
 	}
 }
 
@@ -164,6 +189,7 @@ type Server struct {
 	TrackerServer          *http.Server
 	RewardsServer          *http.Server
 	HttpClient             *http.Client
+	WebhookHttpClient      *http.Client
 	Campfire               *campfire.Client
 	DB                     *database.Database
 	Auth                   *auth.Auth
@@ -171,9 +197,14 @@ type Server struct {
 	Templates              func() *template.Template
 	StaticFS               http.FileSystem
 	WebhookClient          *webhook.Client
+	S3                     *s3.Client
+	PresetExporter         PresetExporter
 	SentTokenNotifications []int
 	Reloader               *goreload.Reloader
 	Logo                   image.Image
+	RaffleUpdates          *RaffleUpdates
+	APIKeyLimiter          *APIKeyLimiter
+	EventConfig            *EventConfigCache
 }
 
 func (s *Server) Start(trackerHandler http.Handler, rewardsHandler http.Handler) {
@@ -191,9 +222,7 @@ func (s *Server) Start(trackerHandler http.Handler, rewardsHandler http.Handler)
 		}
 	}()
 
-	go s.importClubs()
-	go s.importEvents()
-	go s.updateEvents()
+	s.runJobs()
 }
 
 func (s *Server) Stop() {
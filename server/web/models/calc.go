@@ -5,6 +5,39 @@ import (
 	"time"
 )
 
+const (
+	OriginLeagueGoal    = 1
+	GreatLeagueGoal     = 61
+	UltraLeagueGoal     = 250
+	MasterLeagueGoal    = 750
+	LegendaryLeagueGoal = 1500
+)
+
+// League is a Campfire league with the CA check-ins a club needs within a quarter to reach it.
+type League struct {
+	Name string
+	Goal int
+}
+
+var Leagues = []League{
+	{Name: "Origin League", Goal: OriginLeagueGoal},
+	{Name: "Great League", Goal: GreatLeagueGoal},
+	{Name: "Ultra League", Goal: UltraLeagueGoal},
+	{Name: "Master League", Goal: MasterLeagueGoal},
+	{Name: "Legendary League", Goal: LegendaryLeagueGoal},
+}
+
+// GetLeague returns the highest league reached with the CA check-ins or nil if no league was reached.
+func GetLeague(caCheckIns int) *League {
+	var reached *League
+	for i, league := range Leagues {
+		if caCheckIns >= league.Goal {
+			reached = &Leagues[i]
+		}
+	}
+	return reached
+}
+
 func CalcCheckInRate(accepted int, checkIns int) float64 {
 	if checkIns == 0 {
 		return 0
@@ -12,6 +45,29 @@ func CalcCheckInRate(accepted int, checkIns int) float64 {
 	return math.RoundToEven(float64(checkIns) / float64(accepted) * 100)
 }
 
+// CalcReliabilityScore returns the check-in rate of a member as if every member started with one check-in and one no-show,
+// so a few RSVPs do not already result in a perfect or zero score.
+func CalcReliabilityScore(rsvps int, checkIns int) float64 {
+	return math.RoundToEven(float64(checkIns+1) / float64(rsvps+2) * 100)
+}
+
+// CalcGrowth returns the change from previous to current in percent.
+// ok is false if previous is 0, as there is no growth to calculate without a previous value.
+func CalcGrowth(previous int, current int) (growth float64, ok bool) {
+	if previous == 0 {
+		return 0, false
+	}
+	return math.RoundToEven(float64(current-previous) / float64(previous) * 100), true
+}
+
+// CalcAverage returns the average of total over count, rounded to one decimal.
+func CalcAverage(total int, count int) float64 {
+	if count == 0 {
+		return 0
+	}
+	return math.RoundToEven(float64(total)/float64(count)*10) / 10
+}
+
 func CalcQuarterProgress(days int, daysRemaining int) float64 {
 	if days == 0 {
 		return 0
@@ -1,10 +1,11 @@
 package models
 
 import (
+	"cmp"
 	"encoding/json"
 	"fmt"
 	"path"
-	"slices"
+	"strings"
 	"time"
 
 	"github.com/topi314/campfire-tools/server/campfire"
@@ -13,28 +14,46 @@ import (
 
 func NewClub(club database.ClubWithCreator) Club {
 	return Club{
-		ID:                           club.Club.ID,
-		Name:                         club.Club.Name,
-		AvatarURL:                    ImageURL(club.Club.AvatarURL, 48),
-		Creator:                      NewMember(club.Member, club.Club.ID, 32),
-		CreatedByCommunityAmbassador: campfire.ClubCreatedByCommunityAmbassadorFromRaw(club.Club.CreatedByCommunityAmbassador, club.Club.RawJSON),
-		AutoEventImport:              club.Club.AutoEventImport,
-		LastAutoEventImportedAt:      club.Club.LastAutoEventImportedAt,
-		ImportedAt:                   club.Club.ImportedAt,
-		URL:                          fmt.Sprintf("/tracker/club/%s", club.Club.ID),
+		ID:                                club.Club.ID,
+		Name:                              club.Club.Name,
+		AvatarURL:                         ImageURL(club.Club.AvatarURL, 48),
+		Creator:                           NewMember(club.Member, club.Club.ID, 32),
+		CreatedByCommunityAmbassador:      campfire.ClubCreatedByCommunityAmbassadorFromRaw(club.Club.CreatedByCommunityAmbassador, club.Club.RawJSON),
+		AutoEventImport:                   club.Club.AutoEventImport,
+		LastAutoEventImportedAt:           club.Club.LastAutoEventImportedAt,
+		ImportedAt:                        club.Club.ImportedAt,
+		URL:                               fmt.Sprintf("/tracker/club/%s", club.Club.ID),
+		RaffleBlockedMembers:              club.Club.RaffleBlockedMembers,
+		RaffleCooldownDays:                club.Club.RaffleCooldownDays,
+		RaffleExcludeCommunityAmbassadors: club.Club.RaffleExcludeCommunityAmbassadors,
+		DigestWebhookConfigured:           club.Club.DigestWebhookURL != nil,
+		DigestWeekly:                      club.Club.DigestWeekly,
+		DigestQuarterly:                   club.Club.DigestQuarterly,
+		DigestWeekday:                     int(club.Club.DigestWeekday),
+		DigestHour:                        club.Club.DigestHour,
+		LeaderboardOptOut:                 club.Club.LeaderboardOptOut,
 	}
 }
 
 type Club struct {
-	ID                           string
-	Name                         string
-	AvatarURL                    string
-	Creator                      Member
-	CreatedByCommunityAmbassador bool
-	AutoEventImport              bool
-	LastAutoEventImportedAt      time.Time
-	ImportedAt                   time.Time
-	URL                          string
+	ID                                string
+	Name                              string
+	AvatarURL                         string
+	Creator                           Member
+	CreatedByCommunityAmbassador      bool
+	AutoEventImport                   bool
+	LastAutoEventImportedAt           time.Time
+	ImportedAt                        time.Time
+	URL                               string
+	RaffleBlockedMembers              []string
+	RaffleCooldownDays                int
+	RaffleExcludeCommunityAmbassadors bool
+	DigestWebhookConfigured           bool
+	DigestWeekly                      bool
+	DigestQuarterly                   bool
+	DigestWeekday                     int
+	DigestHour                        int
+	LeaderboardOptOut                 bool
 }
 
 func NewClubWithEvents(club database.ClubWithEvents) ClubWithEvents {
@@ -161,15 +180,13 @@ func clubMemberURL(clubID, memberID string) string {
 
 func NewMemberFromCampfire(member campfire.Member, clubID string, iconSize int) Member {
 	return Member{
-		ID:          member.ID,
-		Username:    member.Username,
-		DisplayName: GetDisplayName(member.DisplayName, member.Username),
-		AvatarURL:   ImageURL(member.AvatarURL, iconSize),
-		IsCommunityAmbassador: slices.ContainsFunc(member.Badges, func(badge campfire.Badge) bool {
-			return badge.Alias == "PGO_COMMUNITY_AMBASSADOR"
-		}),
-		URL:        clubMemberURL(clubID, member.ID),
-		ProfileURL: memberProfileURL(member.ID),
+		ID:                    member.ID,
+		Username:              member.Username,
+		DisplayName:           GetDisplayName(member.DisplayName, member.Username),
+		AvatarURL:             ImageURL(member.AvatarURL, iconSize),
+		IsCommunityAmbassador: campfire.MemberIsCommunityAmbassador(member.Badges),
+		URL:                   clubMemberURL(clubID, member.ID),
+		ProfileURL:            memberProfileURL(member.ID),
 	}
 }
 
@@ -190,9 +207,7 @@ func NewImportedMember(member database.Member, iconSize int) Member {
 	if len(member.RawJSON) > 0 && string(member.RawJSON) != "{}" {
 		var campfireMember campfire.Member
 		if err := json.Unmarshal(member.RawJSON, &campfireMember); err == nil {
-			m.IsCommunityAmbassador = slices.ContainsFunc(campfireMember.Badges, func(badge campfire.Badge) bool {
-				return badge.Alias == "PGO_COMMUNITY_AMBASSADOR"
-			})
+			m.IsCommunityAmbassador = campfire.MemberIsCommunityAmbassador(campfireMember.Badges)
 		}
 	}
 
@@ -280,69 +295,143 @@ type TopEvent struct {
 
 func NewRaffle(raffle database.Raffle) Raffle {
 	return Raffle{
-		ID:            raffle.ID,
-		UserID:        raffle.UserID,
-		Events:        raffle.Events,
-		WinnerCount:   raffle.WinnerCount,
-		OnlyCheckedIn: raffle.OnlyCheckedIn,
-		SingleEntry:   raffle.SingleEntry,
-		CreatedAt:     raffle.CreatedAt,
-		URL:           fmt.Sprintf("/raffle/%d", raffle.ID),
+		ID:               raffle.ID,
+		UserID:           raffle.UserID,
+		Events:           raffle.Events,
+		WinnerCount:      raffle.WinnerCount,
+		OnlyCheckedIn:    raffle.OnlyCheckedIn,
+		SingleEntry:      raffle.SingleEntry,
+		CreatedAt:        raffle.CreatedAt,
+		URL:              fmt.Sprintf("/raffle/%d", raffle.ID),
+		Weighting:        RaffleWeightingName(raffle.Weighting),
+		BonusMinCheckIns: raffle.BonusMinCheckIns,
+		BonusTickets:     raffle.BonusTickets,
+		MaxTickets:       raffle.MaxTickets,
+		MinOneTicket:     raffle.MinOneTicket,
+		SeedCommitment:   raffle.SeedCommitment,
+		VerifyURL:        fmt.Sprintf("/raffle/%d/verify", raffle.ID),
 	}
 }
 
 type Raffle struct {
-	ID            int
-	UserID        string
-	Events        []string
-	WinnerCount   int
-	OnlyCheckedIn bool
-	SingleEntry   bool
-	CreatedAt     time.Time
-	URL           string
+	ID               int
+	UserID           string
+	Events           []string
+	WinnerCount      int
+	OnlyCheckedIn    bool
+	SingleEntry      bool
+	CreatedAt        time.Time
+	URL              string
+	Weighting        string
+	BonusMinCheckIns int
+	BonusTickets     int
+	MaxTickets       int
+	MinOneTicket     bool
+	SeedCommitment   string
+	VerifyURL        string
+}
+
+func RaffleWeightingName(weighting database.RaffleWeighting) string {
+	switch weighting {
+	case database.RaffleWeightingCheckIns:
+		return "Tickets per Check-In"
+	default:
+		return "None"
+	}
 }
 
 func NewWinner(winner database.RaffleWinnerWithMember, clubID string) Winner {
 	var confirmURL string
+	var winnerURL string
 	if clubID != "" {
 		confirmURL = fmt.Sprintf("/tracker/club/%s/raffle/%d/confirm/%s", clubID, winner.RaffleID, winner.Member.ID)
+		winnerURL = fmt.Sprintf("/tracker/club/%s/raffle/%d/winner/%s", clubID, winner.RaffleID, winner.Member.ID)
 	} else {
 		confirmURL = fmt.Sprintf("/raffle/%d/confirm/%s", winner.RaffleID, winner.Member.ID)
+		winnerURL = fmt.Sprintf("/raffle/%d/winner/%s", winner.RaffleID, winner.Member.ID)
+	}
+
+	var rewardCodeQRURL string
+	if winner.RewardID != nil && winner.RewardCodeID != nil {
+		rewardCodeQRURL = fmt.Sprintf("/tracker/rewards/%d/codes/%d/qr", *winner.RewardID, *winner.RewardCodeID)
 	}
 
 	return Winner{
-		Member:     NewMember(winner.Member, clubID, 32),
-		Accepted:   winner.Accepted,
-		CheckIns:   winner.CheckIns,
-		Confirmed:  winner.Confirmed,
-		Previous:   winner.Past,
-		ConfirmURL: confirmURL,
+		Member:          NewMember(winner.Member, clubID, 32),
+		Accepted:        winner.Accepted,
+		CheckIns:        winner.CheckIns,
+		Confirmed:       winner.Confirmed,
+		Previous:        winner.Past,
+		ConfirmURL:      confirmURL,
+		PrizeTier:       winner.PrizeTierName,
+		HasReward:       winner.RewardID != nil,
+		RewardCode:      winner.RewardCode,
+		WinnerURL:       winnerURL,
+		RewardCodeQRURL: rewardCodeQRURL,
 	}
 }
 
 type Winner struct {
 	Member
-	Accepted   int
-	CheckIns   int
-	Confirmed  bool
-	Previous   bool
-	ConfirmURL string
+	Accepted        int
+	CheckIns        int
+	Confirmed       bool
+	Previous        bool
+	ConfirmURL      string
+	PrizeTier       string
+	HasReward       bool
+	RewardCode      string
+	WinnerURL       string
+	RewardCodeQRURL string
+}
+
+func NewRafflePrizeTier(tier database.RafflePrizeTierWithReward) RafflePrizeTier {
+	return RafflePrizeTier{
+		Name:       tier.Name,
+		Count:      tier.Count,
+		RewardName: tier.RewardName,
+	}
+}
+
+type RafflePrizeTier struct {
+	Name       string
+	Count      int
+	RewardName string
 }
 
-func NewToken(token database.CampfireToken) Token {
-	return Token{
+func NewToken(token database.CampfireToken, stats *campfire.TokenStats) Token {
+	t := Token{
 		ID:        token.ID,
 		Token:     token.Token,
 		ExpiresAt: token.ExpiresAt,
 		Email:     token.Email,
 	}
+	if stats != nil {
+		t.InPool = true
+		t.Healthy = stats.Healthy
+		t.CoolingDown = stats.CooldownUntil.After(time.Now())
+		t.Requests = stats.Requests
+		t.RateLimited = stats.RateLimited
+		t.AuthFailures = stats.AuthFailures
+		t.LastUsedAt = stats.LastUsedAt
+		t.LastError = stats.LastError
+	}
+	return t
 }
 
 type Token struct {
-	ID        int
-	Token     string
-	ExpiresAt time.Time
-	Email     string
+	ID           int
+	Token        string
+	ExpiresAt    time.Time
+	Email        string
+	InPool       bool
+	Healthy      bool
+	CoolingDown  bool
+	Requests     int
+	RateLimited  int
+	AuthFailures int
+	LastUsedAt   time.Time
+	LastError    string
 }
 
 func NewClubImportJob(job database.ClubImportJobWithClub) ClubImportJob {
@@ -388,6 +477,40 @@ func ImageURL(imageURL string, size int) string {
 	return imageURL
 }
 
+func NewJob(job database.Job) Job {
+	return Job{
+		ID:          job.ID,
+		Kind:        string(job.Kind),
+		Key:         job.Key,
+		Priority:    job.Priority,
+		Status:      string(job.Status),
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		NextRunAt:   job.NextRunAt,
+		LastError:   job.LastError,
+		CreatedAt:   job.CreatedAt,
+		UpdatedAt:   job.UpdatedAt,
+		Retryable:   job.Status == database.JobStatusFailed || job.Status == database.JobStatusCancelled,
+		Cancellable: job.Status == database.JobStatusQueued,
+	}
+}
+
+type Job struct {
+	ID          int
+	Kind        string
+	Key         string
+	Priority    int
+	Status      string
+	Attempts    int
+	MaxAttempts int
+	NextRunAt   time.Time
+	LastError   string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Retryable   bool
+	Cancellable bool
+}
+
 func NewReward(reward database.Reward) Reward {
 	return Reward{
 		ID:            reward.ID,
@@ -411,3 +534,234 @@ type Reward struct {
 	RedeemedCodes int
 	TotalCodes    int
 }
+
+func NewAPIKey(key database.APIKey, clubNames map[string]string) APIKey {
+	clubs := make([]string, len(key.ClubIDs))
+	for i, clubID := range key.ClubIDs {
+		clubs[i] = cmp.Or(clubNames[clubID], clubID)
+	}
+
+	return APIKey{
+		ID:         key.ID,
+		URL:        fmt.Sprintf("/tracker/settings/api-keys/%d", key.ID),
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		Clubs:      clubs,
+		RateLimit:  key.RateLimit,
+		CreatedAt:  key.CreatedAt,
+		LastUsedAt: key.LastUsedAt,
+	}
+}
+
+type APIKey struct {
+	ID         int
+	URL        string
+	Name       string
+	Prefix     string
+	Scopes     []string
+	Clubs      []string
+	RateLimit  int
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+}
+
+func NewWebhook(webhook database.Webhook) Webhook {
+	return Webhook{
+		ID:        webhook.ID,
+		URL:       fmt.Sprintf("/tracker/club/%s/webhooks/%d", webhook.ClubID, webhook.ID),
+		TargetURL: webhook.URL,
+		Topics:    webhook.Topics,
+		CreatedAt: webhook.CreatedAt,
+	}
+}
+
+type Webhook struct {
+	ID        int
+	URL       string
+	TargetURL string
+	Topics    []string
+	CreatedAt time.Time
+}
+
+func NewWebhookDelivery(delivery database.WebhookDeliveryWithWebhook) WebhookDelivery {
+	return WebhookDelivery{
+		ID:             delivery.WebhookDelivery.ID,
+		TargetURL:      delivery.Webhook.URL,
+		Topic:          string(delivery.Topic),
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		ResponseStatus: delivery.ResponseStatus,
+		Error:          delivery.Error,
+		CreatedAt:      delivery.WebhookDelivery.CreatedAt,
+		AttemptedAt:    delivery.AttemptedAt,
+	}
+}
+
+type WebhookDelivery struct {
+	ID             int
+	TargetURL      string
+	Topic          string
+	Status         string
+	Attempts       int
+	ResponseStatus *int
+	Error          string
+	CreatedAt      time.Time
+	AttemptedAt    *time.Time
+}
+
+func NewExportPreset(preset database.ExportPreset, eventRange string) ExportPreset {
+	return ExportPreset{
+		ID:         preset.ID,
+		URL:        fmt.Sprintf("/tracker/club/%s/export/presets/%d", preset.ClubID, preset.ID),
+		RunURL:     fmt.Sprintf("/tracker/club/%s/export?preset=%d", preset.ClubID, preset.ID),
+		Name:       preset.Name,
+		Format:     preset.Format,
+		Fields:     len(preset.IncludedFields),
+		EventRange: eventRange,
+		CreatedAt:  preset.CreatedAt,
+	}
+}
+
+type ExportPreset struct {
+	ID         int
+	URL        string
+	RunURL     string
+	Name       string
+	Format     string
+	Fields     int
+	EventRange string
+	CreatedAt  time.Time
+}
+
+func NewScheduledExport(scheduledExport database.ScheduledExportWithPreset, eventRange string, destination string) ScheduledExport {
+	return ScheduledExport{
+		ID:          scheduledExport.ScheduledExport.ID,
+		URL:         fmt.Sprintf("/tracker/club/%s/scheduled-exports/%d", scheduledExport.ScheduledExport.ClubID, scheduledExport.ScheduledExport.ID),
+		PresetName:  scheduledExport.Name,
+		EventRange:  eventRange,
+		Format:      scheduledExport.Format,
+		Schedule:    scheduledExport.Schedule,
+		Destination: destination,
+		NextRunAt:   scheduledExport.NextRunAt,
+		CreatedAt:   scheduledExport.ScheduledExport.CreatedAt,
+	}
+}
+
+type ScheduledExport struct {
+	ID          int
+	URL         string
+	PresetName  string
+	EventRange  string
+	Format      string
+	Schedule    string
+	Destination string
+	NextRunAt   time.Time
+	CreatedAt   time.Time
+}
+
+func NewScheduledExportRun(run database.ScheduledExportRunWithExport) ScheduledExportRun {
+	return ScheduledExportRun{
+		ID:          run.ScheduledExportRun.ID,
+		PresetName:  run.Name,
+		Destination: string(run.Destination),
+		ScheduledAt: run.ScheduledAt,
+		Status:      string(run.Status),
+		Attempts:    run.Attempts,
+		Events:      run.Events,
+		FileName:    run.FileName,
+		Error:       run.Error,
+		AttemptedAt: run.AttemptedAt,
+	}
+}
+
+type ScheduledExportRun struct {
+	ID          int
+	PresetName  string
+	Destination string
+	ScheduledAt time.Time
+	Status      string
+	Attempts    int
+	Events      int
+	FileName    string
+	Error       string
+	AttemptedAt *time.Time
+}
+
+func NewEventCategoryConfig(category database.EventCategory, events int) EventCategoryConfig {
+	return EventCategoryConfig{
+		ID:       category.ID,
+		URL:      fmt.Sprintf("/admin/event-categories/%d", category.ID),
+		Name:     category.Name,
+		Patterns: category.Patterns,
+		Position: category.Position,
+		Events:   events,
+	}
+}
+
+type EventCategoryConfig struct {
+	ID       int
+	URL      string
+	Name     string
+	Patterns []string
+	Position int
+	Events   int
+}
+
+func NewDigitalCodeExcludePattern(pattern database.DigitalCodeExcludePattern) DigitalCodeExcludePattern {
+	return DigitalCodeExcludePattern{
+		ID:      pattern.ID,
+		URL:     fmt.Sprintf("/admin/digital-code-exclude-patterns/%d", pattern.ID),
+		Pattern: pattern.Pattern,
+	}
+}
+
+type DigitalCodeExcludePattern struct {
+	ID      int
+	URL     string
+	Pattern string
+}
+
+func NewConfiguredEventConfig(event database.ConfiguredEvent) ConfiguredEventConfig {
+	days := make([]string, len(event.Days.V))
+	for i, day := range event.Days.V {
+		days[i] = day.Label + ": " + day.LiveEventID
+	}
+	return ConfiguredEventConfig{
+		ID:       event.ID,
+		URL:      fmt.Sprintf("/admin/configured-events/%d", event.ID),
+		Key:      event.Key,
+		Name:     event.Name,
+		Days:     strings.Join(days, "\n"),
+		DayCount: len(event.Days.V),
+		Position: event.Position,
+	}
+}
+
+type ConfiguredEventConfig struct {
+	ID       int
+	URL      string
+	Key      string
+	Name     string
+	Days     string
+	DayCount int
+	Position int
+}
+
+func NewLiveEvent(liveEvent database.LiveEvent, category string) LiveEvent {
+	return LiveEvent{
+		ID:          liveEvent.ID,
+		Name:        liveEvent.Name,
+		Events:      liveEvent.Events,
+		LastEventAt: liveEvent.LastEventAt,
+		Category:    category,
+	}
+}
+
+type LiveEvent struct {
+	ID          string
+	Name        string
+	Events      int
+	LastEventAt time.Time
+	Category    string
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"github.com/topi314/campfire-tools/internal/xrand"
+	"github.com/topi314/campfire-tools/server/database"
+)
+
+// NewRaffleDraw builds the verification view of a recorded draw.
+// computedWinners are the member IDs drawn again from the recorded seed and entrant snapshot.
+func NewRaffleDraw(number int, draw database.RaffleDraw, computedWinners []string) RaffleDraw {
+	names := make(map[string]string, len(draw.Entrants.V))
+	entrants := make([]RaffleDrawEntrant, 0, len(draw.Entrants.V))
+	var totalTickets int
+	for _, entrant := range draw.Entrants.V {
+		names[entrant.MemberID] = entrant.DisplayName
+		totalTickets += entrant.Tickets
+		entrants = append(entrants, RaffleDrawEntrant{
+			MemberID:    entrant.MemberID,
+			DisplayName: entrant.DisplayName,
+			Tickets:     entrant.Tickets,
+		})
+	}
+
+	drawWinners := func(ids []string) []RaffleDrawEntrant {
+		winners := make([]RaffleDrawEntrant, 0, len(ids))
+		for _, id := range ids {
+			winners = append(winners, RaffleDrawEntrant{
+				MemberID:    id,
+				DisplayName: names[id],
+			})
+		}
+		return winners
+	}
+
+	winnersMatch := len(draw.Winners) == len(computedWinners)
+	for i := 0; winnersMatch && i < len(computedWinners); i++ {
+		winnersMatch = draw.Winners[i] == computedWinners[i]
+	}
+
+	return RaffleDraw{
+		Number:          number,
+		CreatedAt:       draw.CreatedAt,
+		SeedCommitment:  draw.SeedCommitment,
+		Seed:            draw.Seed,
+		CommitmentValid: xrand.SeedCommitment(draw.Seed) == draw.SeedCommitment,
+		WinnerCount:     draw.WinnerCount,
+		Entrants:        entrants,
+		TotalTickets:    totalTickets,
+		Winners:         drawWinners(draw.Winners),
+		ComputedWinners: drawWinners(computedWinners),
+		WinnersMatch:    winnersMatch,
+	}
+}
+
+type RaffleDraw struct {
+	Number          int
+	CreatedAt       time.Time
+	SeedCommitment  string
+	Seed            string
+	CommitmentValid bool
+	WinnerCount     int
+	Entrants        []RaffleDrawEntrant
+	TotalTickets    int
+	Winners         []RaffleDrawEntrant
+	ComputedWinners []RaffleDrawEntrant
+	WinnersMatch    bool
+}
+
+type RaffleDrawEntrant struct {
+	MemberID    string
+	DisplayName string
+	Tickets     int
+}
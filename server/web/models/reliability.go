@@ -0,0 +1,95 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"github.com/topi314/campfire-tools/server/database"
+)
+
+// NewReliability calculates how reliably a member checks in to the events they accepted. Accepted events only count as
+// no-show once they ended, until then the member can still check in.
+func NewReliability(checkedInEvents []database.Event, acceptedEvents []database.Event, now time.Time) Reliability {
+	type rsvp struct {
+		time      time.Time
+		checkedIn bool
+	}
+
+	rsvps := make([]rsvp, 0, len(checkedInEvents)+len(acceptedEvents))
+	for _, event := range checkedInEvents {
+		rsvps = append(rsvps, rsvp{time: event.Time, checkedIn: true})
+	}
+
+	var reliability Reliability
+	for _, event := range acceptedEvents {
+		if !event.Finished && event.EndTime.After(now) {
+			reliability.Upcoming++
+			continue
+		}
+		rsvps = append(rsvps, rsvp{time: event.Time})
+	}
+	slices.SortFunc(rsvps, func(a, b rsvp) int {
+		return b.time.Compare(a.time)
+	})
+
+	streak := 0
+	currentStreak := true
+	for _, r := range rsvps {
+		reliability.RSVPs++
+		if r.checkedIn {
+			reliability.CheckIns++
+			streak = 0
+			currentStreak = false
+			continue
+		}
+
+		reliability.NoShows++
+		if reliability.LastNoShowAt.IsZero() {
+			reliability.LastNoShowAt = r.time
+		}
+		streak++
+		if currentStreak {
+			reliability.NoShowStreak = streak
+		}
+		reliability.LongestNoShowStreak = max(reliability.LongestNoShowStreak, streak)
+	}
+
+	reliability.Conversion = CalcCheckInRate(reliability.RSVPs, reliability.CheckIns)
+	reliability.Score = CalcReliabilityScore(reliability.RSVPs, reliability.CheckIns)
+
+	return reliability
+}
+
+// Reliability is how many of the events a member accepted they checked in to.
+// The no-show streak are the no-shows since their last check-in, the longest streak is the most no-shows in a row.
+type Reliability struct {
+	RSVPs               int
+	CheckIns            int
+	NoShows             int
+	Upcoming            int
+	Conversion          float64
+	Score               float64
+	NoShowStreak        int
+	LongestNoShowStreak int
+	LastNoShowAt        time.Time
+}
+
+func NewNoShowMember(member database.NoShowMember, clubID string, iconSize int) NoShowMember {
+	return NoShowMember{
+		Member:       NewMember(member.Member, clubID, iconSize),
+		RSVPs:        member.RSVPs,
+		CheckIns:     member.CheckIns,
+		NoShows:      member.NoShows,
+		Score:        CalcReliabilityScore(member.RSVPs, member.CheckIns),
+		LastNoShowAt: member.LastNoShowAt,
+	}
+}
+
+type NoShowMember struct {
+	Member
+	RSVPs        int
+	CheckIns     int
+	NoShows      int
+	Score        float64
+	LastNoShowAt time.Time
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/topi314/campfire-tools/server/database"
+)
+
+// NewRSVPTimeline builds the RSVP timeline of an event. Lead times are measured from the first time a member
+// was observed as ACCEPTED or CHECKED_IN to the start of the event.
+func NewRSVPTimeline(event database.Event, history []database.EventRSVPHistoryWithMember) RSVPTimeline {
+	timeline := RSVPTimeline{
+		Transitions: make([]RSVPTransition, 0, len(history)),
+	}
+
+	committed := make(map[string]struct{})
+	for _, entry := range history {
+		var oldStatus string
+		if entry.OldStatus != nil {
+			oldStatus = *entry.OldStatus
+		}
+		leadTime := event.Time.Sub(entry.ObservedAt)
+
+		timeline.Transitions = append(timeline.Transitions, RSVPTransition{
+			Member:     NewMember(entry.Member, event.ClubID, 32),
+			OldStatus:  oldStatus,
+			NewStatus:  entry.NewStatus,
+			ObservedAt: entry.ObservedAt,
+			LeadTime:   FormatLeadTime(leadTime),
+		})
+
+		if entry.NewStatus != "ACCEPTED" && entry.NewStatus != "CHECKED_IN" {
+			continue
+		}
+		if _, ok := committed[entry.MemberID]; ok {
+			continue
+		}
+		committed[entry.MemberID] = struct{}{}
+
+		switch {
+		case leadTime >= 7*24*time.Hour:
+			timeline.CommittedWeekBefore++
+		case leadTime >= 24*time.Hour:
+			timeline.CommittedDaysBefore++
+		case leadTime > 0:
+			timeline.CommittedDayBefore++
+		default:
+			timeline.CommittedAfterStart++
+		}
+	}
+
+	return timeline
+}
+
+type RSVPTimeline struct {
+	Transitions         []RSVPTransition
+	CommittedWeekBefore int
+	CommittedDaysBefore int
+	CommittedDayBefore  int
+	CommittedAfterStart int
+}
+
+type RSVPTransition struct {
+	Member     Member
+	OldStatus  string
+	NewStatus  string
+	ObservedAt time.Time
+	LeadTime   string
+}
+
+// FormatLeadTime formats the duration between an observation and the event start like "2d 4h before start".
+func FormatLeadTime(d time.Duration) string {
+	suffix := "before start"
+	if d < 0 {
+		d = -d
+		suffix = "after start"
+	}
+
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh %s", days, hours, suffix)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm %s", hours, minutes, suffix)
+	default:
+		return fmt.Sprintf("%dm %s", minutes, suffix)
+	}
+}
@@ -7,17 +7,26 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/topi314/campfire-tools/server/auth"
+	"github.com/topi314/campfire-tools/server/campfire"
 	"github.com/topi314/campfire-tools/server/database"
 	"github.com/topi314/campfire-tools/server/web/models"
 )
 
+const adminJobsLimit = 200
+
 type AdminVars struct {
-	Tokens []models.Token
-	Errors []string
+	Tokens        []models.Token
+	Jobs          []models.Job
+	QueuedJobs    int
+	RunningJobs   int
+	FailedJobs    int
+	CancelledJobs int
+	Errors        []string
 }
 
 func (h *handler) Admin(w http.ResponseWriter, r *http.Request) {
@@ -39,14 +48,43 @@ func (h *handler) renderAdmin(w http.ResponseWriter, r *http.Request, errorMessa
 		http.Error(w, "Failed to fetch tokens: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	tokenStats := make(map[int]campfire.TokenStats)
+	for _, stats := range h.Campfire.Tokens.Stats() {
+		tokenStats[stats.ID] = stats
+	}
 	var tokenList []models.Token
 	for _, t := range tokens {
-		tokenList = append(tokenList, models.NewToken(t))
+		var stats *campfire.TokenStats
+		if s, ok := tokenStats[t.ID]; ok {
+			stats = &s
+		}
+		tokenList = append(tokenList, models.NewToken(t, stats))
+	}
+
+	jobs, err := h.DB.GetJobs(ctx, []database.JobStatus{database.JobStatusQueued, database.JobStatusRunning, database.JobStatusFailed, database.JobStatusCancelled}, adminJobsLimit)
+	if err != nil {
+		http.Error(w, "Failed to fetch jobs: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	jobList := make([]models.Job, len(jobs))
+	for i, job := range jobs {
+		jobList[i] = models.NewJob(job)
+	}
+
+	jobCounts, err := h.DB.GetJobCounts(ctx)
+	if err != nil {
+		http.Error(w, "Failed to fetch job counts: "+err.Error(), http.StatusInternalServerError)
+		return
 	}
 
 	if err = h.Templates().ExecuteTemplate(w, "admin.gohtml", AdminVars{
-		Tokens: tokenList,
-		Errors: errorMessages,
+		Tokens:        tokenList,
+		Jobs:          jobList,
+		QueuedJobs:    jobCounts[database.JobStatusQueued],
+		RunningJobs:   jobCounts[database.JobStatusRunning],
+		FailedJobs:    jobCounts[database.JobStatusFailed],
+		CancelledJobs: jobCounts[database.JobStatusCancelled],
+		Errors:        errorMessages,
 	}); err != nil {
 		slog.ErrorContext(ctx, "Failed to render tracker template", slog.Any("err", err))
 	}
@@ -81,6 +119,52 @@ func (h *handler) AdminTokens(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/admin", http.StatusSeeOther)
 }
 
+func (h *handler) AdminRetryJob(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	session := auth.GetSession(r)
+
+	if !session.Admin {
+		h.NotFound(w, r)
+		return
+	}
+
+	jobID, err := strconv.Atoi(r.PathValue("job_id"))
+	if err != nil {
+		h.renderAdmin(w, r, "Invalid job ID: "+err.Error())
+		return
+	}
+
+	if err = h.DB.RetryJob(ctx, jobID); err != nil {
+		h.renderAdmin(w, r, "Failed to retry job: "+err.Error())
+		return
+	}
+
+	http.Redirect(w, r, "/admin", http.StatusSeeOther)
+}
+
+func (h *handler) AdminCancelJob(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	session := auth.GetSession(r)
+
+	if !session.Admin {
+		h.NotFound(w, r)
+		return
+	}
+
+	jobID, err := strconv.Atoi(r.PathValue("job_id"))
+	if err != nil {
+		h.renderAdmin(w, r, "Invalid job ID: "+err.Error())
+		return
+	}
+
+	if err = h.DB.CancelJob(ctx, jobID); err != nil {
+		h.renderAdmin(w, r, "Failed to cancel job: "+err.Error())
+		return
+	}
+
+	http.Redirect(w, r, "/admin", http.StatusSeeOther)
+}
+
 func parseToken(token string) (*database.CampfireToken, error) {
 	parts := strings.Split(token, ".")
 	if len(parts) != 3 {
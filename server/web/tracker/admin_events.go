@@ -0,0 +1,422 @@
+package tracker
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/topi314/campfire-tools/internal/xpgtype"
+	"github.com/topi314/campfire-tools/server/auth"
+	"github.com/topi314/campfire-tools/server/database"
+	"github.com/topi314/campfire-tools/server/web/models"
+)
+
+const adminLiveEventsLimit = 50
+
+var configuredEventKeyRegex = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+type AdminEventsVars struct {
+	Categories         []models.EventCategoryConfig
+	ExcludePatterns    []models.DigitalCodeExcludePattern
+	ConfiguredEvents   []models.ConfiguredEventConfig
+	UnmappedLiveEvents []models.LiveEvent
+	LiveEvents         []models.LiveEvent
+	NextPosition       int
+	NextEventPosition  int
+	Errors             []string
+}
+
+func (h *handler) AdminEvents(w http.ResponseWriter, r *http.Request) {
+	session := auth.GetSession(r)
+
+	if !session.Admin {
+		h.NotFound(w, r)
+		return
+	}
+
+	h.renderAdminEvents(w, r)
+}
+
+func (h *handler) renderAdminEvents(w http.ResponseWriter, r *http.Request, errorMessages ...string) {
+	ctx := r.Context()
+
+	categories, err := h.DB.GetEventCategories(ctx)
+	if err != nil {
+		http.Error(w, "Failed to fetch event categories: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	excludePatterns, err := h.DB.GetDigitalCodeExcludePatterns(ctx)
+	if err != nil {
+		http.Error(w, "Failed to fetch digital code exclude patterns: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	configuredEvents, err := h.DB.GetConfiguredEvents(ctx)
+	if err != nil {
+		http.Error(w, "Failed to fetch configured events: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	liveEvents, err := h.DB.GetLiveEvents(ctx)
+	if err != nil {
+		http.Error(w, "Failed to fetch live events: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// live events of the same name are suggested once, the most recent first
+	categoryEvents := make(map[string]int)
+	unmapped := make(map[string]int)
+	var trackerUnmappedLiveEvents []models.LiveEvent
+	var trackerLiveEvents []models.LiveEvent
+	for _, liveEvent := range liveEvents {
+		category := eventCategories(categories).fromName(liveEvent.Name)
+		categoryEvents[category] += liveEvent.Events
+
+		trackerLiveEvent := models.NewLiveEvent(liveEvent, category)
+		if len(trackerLiveEvents) < adminLiveEventsLimit {
+			trackerLiveEvents = append(trackerLiveEvents, trackerLiveEvent)
+		}
+		if category != EventCategoryOther {
+			continue
+		}
+		name := strings.ToLower(strings.TrimSpace(liveEvent.Name))
+		if i, ok := unmapped[name]; ok {
+			trackerUnmappedLiveEvents[i].Events += liveEvent.Events
+			continue
+		}
+		unmapped[name] = len(trackerUnmappedLiveEvents)
+		trackerUnmappedLiveEvents = append(trackerUnmappedLiveEvents, trackerLiveEvent)
+	}
+	if len(trackerUnmappedLiveEvents) > adminLiveEventsLimit {
+		trackerUnmappedLiveEvents = trackerUnmappedLiveEvents[:adminLiveEventsLimit]
+	}
+
+	trackerCategories := make([]models.EventCategoryConfig, len(categories))
+	nextPosition := 1
+	for i, category := range categories {
+		trackerCategories[i] = models.NewEventCategoryConfig(category, categoryEvents[category.Name])
+		nextPosition = max(nextPosition, category.Position+1)
+	}
+
+	trackerExcludePatterns := make([]models.DigitalCodeExcludePattern, len(excludePatterns))
+	for i, pattern := range excludePatterns {
+		trackerExcludePatterns[i] = models.NewDigitalCodeExcludePattern(pattern)
+	}
+
+	trackerConfiguredEvents := make([]models.ConfiguredEventConfig, len(configuredEvents))
+	nextEventPosition := 1
+	for i, event := range configuredEvents {
+		trackerConfiguredEvents[i] = models.NewConfiguredEventConfig(event)
+		nextEventPosition = max(nextEventPosition, event.Position+1)
+	}
+
+	if err = h.Templates().ExecuteTemplate(w, "admin_events.gohtml", AdminEventsVars{
+		Categories:         trackerCategories,
+		ExcludePatterns:    trackerExcludePatterns,
+		ConfiguredEvents:   trackerConfiguredEvents,
+		UnmappedLiveEvents: trackerUnmappedLiveEvents,
+		LiveEvents:         trackerLiveEvents,
+		NextPosition:       nextPosition,
+		NextEventPosition:  nextEventPosition,
+		Errors:             errorMessages,
+	}); err != nil {
+		slog.ErrorContext(ctx, "Failed to render tracker template", slog.Any("err", err))
+	}
+}
+
+// redirectAdminEvents reloads the cached event config after a change and shows the admin events page again.
+func (h *handler) redirectAdminEvents(w http.ResponseWriter, r *http.Request) {
+	h.EventConfig.Invalidate()
+	http.Redirect(w, r, "/admin/events", http.StatusSeeOther)
+}
+
+// PostAdminEventCategory creates an event category, or updates it if the request has an event category ID.
+func (h *handler) PostAdminEventCategory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	session := auth.GetSession(r)
+
+	if !session.Admin {
+		h.NotFound(w, r)
+		return
+	}
+
+	category, err := parseEventCategory(r)
+	if err != nil {
+		h.renderAdminEvents(w, r, "Invalid event category: "+err.Error())
+		return
+	}
+
+	if category.ID == 0 {
+		_, err = h.DB.InsertEventCategory(ctx, *category)
+	} else {
+		err = h.DB.UpdateEventCategory(ctx, *category)
+	}
+	if err != nil {
+		h.renderAdminEvents(w, r, "Failed to save event category: "+err.Error())
+		return
+	}
+
+	h.redirectAdminEvents(w, r)
+}
+
+func parseEventCategory(r *http.Request) (*database.EventCategory, error) {
+	var id int
+	if idStr := r.PathValue("event_category_id"); idStr != "" {
+		var err error
+		if id, err = strconv.Atoi(idStr); err != nil {
+			return nil, fmt.Errorf("invalid event category ID: %w", err)
+		}
+	}
+
+	name := strings.TrimSpace(r.FormValue("name"))
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+	if strings.EqualFold(name, EventCategoryOther) || strings.EqualFold(name, EventCategoryNoEvent) {
+		return nil, fmt.Errorf("%s is reserved for events without a category", name)
+	}
+
+	position, err := strconv.Atoi(r.FormValue("position"))
+	if err != nil {
+		return nil, errors.New("position must be a number")
+	}
+
+	patterns := parseLines(r.FormValue("patterns"))
+	if len(patterns) == 0 {
+		return nil, errors.New("at least one pattern is required")
+	}
+
+	return &database.EventCategory{
+		ID:       id,
+		Name:     name,
+		Patterns: patterns,
+		Position: position,
+	}, nil
+}
+
+// PostAdminEventCategoryPattern adds the pattern of an unmapped live event to an event category.
+func (h *handler) PostAdminEventCategoryPattern(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	session := auth.GetSession(r)
+
+	if !session.Admin {
+		h.NotFound(w, r)
+		return
+	}
+
+	id, err := strconv.Atoi(r.FormValue("event_category_id"))
+	if err != nil {
+		h.renderAdminEvents(w, r, "Select an event category")
+		return
+	}
+	pattern := strings.TrimSpace(r.FormValue("pattern"))
+	if pattern == "" {
+		h.renderAdminEvents(w, r, "Enter a pattern")
+		return
+	}
+
+	if err = h.DB.AddEventCategoryPattern(ctx, id, pattern); err != nil {
+		h.renderAdminEvents(w, r, "Failed to add pattern: "+err.Error())
+		return
+	}
+
+	h.redirectAdminEvents(w, r)
+}
+
+func (h *handler) AdminEventCategoryDelete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	session := auth.GetSession(r)
+
+	if !session.Admin {
+		h.NotFound(w, r)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("event_category_id"))
+	if err != nil {
+		h.renderAdminEvents(w, r, "Invalid event category ID: "+err.Error())
+		return
+	}
+
+	if err = h.DB.DeleteEventCategory(ctx, id); err != nil {
+		h.renderAdminEvents(w, r, "Failed to delete event category: "+err.Error())
+		return
+	}
+
+	h.redirectAdminEvents(w, r)
+}
+
+func (h *handler) PostAdminDigitalCodeExcludePattern(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	session := auth.GetSession(r)
+
+	if !session.Admin {
+		h.NotFound(w, r)
+		return
+	}
+
+	pattern := strings.TrimSpace(r.FormValue("pattern"))
+	if pattern == "" {
+		h.renderAdminEvents(w, r, "Enter a pattern")
+		return
+	}
+
+	if err := h.DB.InsertDigitalCodeExcludePattern(ctx, pattern); err != nil {
+		h.renderAdminEvents(w, r, "Failed to add digital code exclude pattern: "+err.Error())
+		return
+	}
+
+	h.redirectAdminEvents(w, r)
+}
+
+func (h *handler) AdminDigitalCodeExcludePatternDelete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	session := auth.GetSession(r)
+
+	if !session.Admin {
+		h.NotFound(w, r)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("pattern_id"))
+	if err != nil {
+		h.renderAdminEvents(w, r, "Invalid pattern ID: "+err.Error())
+		return
+	}
+
+	if err = h.DB.DeleteDigitalCodeExcludePattern(ctx, id); err != nil {
+		h.renderAdminEvents(w, r, "Failed to delete digital code exclude pattern: "+err.Error())
+		return
+	}
+
+	h.redirectAdminEvents(w, r)
+}
+
+// PostAdminConfiguredEvent creates a configured event, or updates it if the request has a configured event ID.
+func (h *handler) PostAdminConfiguredEvent(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	session := auth.GetSession(r)
+
+	if !session.Admin {
+		h.NotFound(w, r)
+		return
+	}
+
+	event, err := parseConfiguredEvent(r)
+	if err != nil {
+		h.renderAdminEvents(w, r, "Invalid configured event: "+err.Error())
+		return
+	}
+
+	if event.ID == 0 {
+		_, err = h.DB.InsertConfiguredEvent(ctx, *event)
+	} else {
+		err = h.DB.UpdateConfiguredEvent(ctx, *event)
+	}
+	if err != nil {
+		h.renderAdminEvents(w, r, "Failed to save configured event: "+err.Error())
+		return
+	}
+
+	h.redirectAdminEvents(w, r)
+}
+
+func parseConfiguredEvent(r *http.Request) (*database.ConfiguredEvent, error) {
+	var id int
+	if idStr := r.PathValue("configured_event_id"); idStr != "" {
+		var err error
+		if id, err = strconv.Atoi(idStr); err != nil {
+			return nil, fmt.Errorf("invalid configured event ID: %w", err)
+		}
+	}
+
+	key := strings.TrimSpace(r.FormValue("key"))
+	if !configuredEventKeyRegex.MatchString(key) {
+		return nil, errors.New("key may only contain lowercase letters, numbers and dashes, like go-fest-2026")
+	}
+
+	name := strings.TrimSpace(r.FormValue("name"))
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	position, err := strconv.Atoi(r.FormValue("position"))
+	if err != nil {
+		return nil, errors.New("position must be a number")
+	}
+
+	days, err := parseConfiguredEventDays(r.FormValue("days"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &database.ConfiguredEvent{
+		ID:       id,
+		Key:      key,
+		Name:     name,
+		Days:     xpgtype.NewJSON(days),
+		Position: position,
+	}, nil
+}
+
+// parseConfiguredEventDays parses one day per line in the format "Label: live event ID". The live event ID may be left empty until it is known.
+func parseConfiguredEventDays(value string) ([]database.ConfiguredEventDay, error) {
+	var days []database.ConfiguredEventDay
+	for _, line := range parseLines(value) {
+		label, liveEventID, ok := strings.Cut(line, ":")
+		label = strings.TrimSpace(label)
+		if !ok || label == "" {
+			return nil, fmt.Errorf("invalid day %q, use one line per day like \"Saturday: <live event ID>\"", line)
+		}
+		days = append(days, database.ConfiguredEventDay{
+			Label:       label,
+			LiveEventID: strings.TrimSpace(liveEventID),
+		})
+	}
+	if len(days) == 0 {
+		return nil, errors.New("at least one day is required")
+	}
+	return days, nil
+}
+
+func (h *handler) AdminConfiguredEventDelete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	session := auth.GetSession(r)
+
+	if !session.Admin {
+		h.NotFound(w, r)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("configured_event_id"))
+	if err != nil {
+		h.renderAdminEvents(w, r, "Invalid configured event ID: "+err.Error())
+		return
+	}
+
+	if err = h.DB.DeleteConfiguredEvent(ctx, id); err != nil {
+		h.renderAdminEvents(w, r, "Failed to delete configured event: "+err.Error())
+		return
+	}
+
+	h.redirectAdminEvents(w, r)
+}
+
+// parseLines returns the trimmed, non-empty and unique lines of the value.
+func parseLines(value string) []string {
+	var lines []string
+	for line := range strings.Lines(value) {
+		line = strings.TrimSpace(line)
+		if line == "" || slices.Contains(lines, line) {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
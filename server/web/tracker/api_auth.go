@@ -0,0 +1,69 @@
+package tracker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/topi314/campfire-tools/server"
+	"github.com/topi314/campfire-tools/server/database"
+)
+
+type apiKeyKey struct{}
+
+var apiKeyContextKey = &apiKeyKey{}
+
+// apiAuth only lets requests through which send a valid API key with the given scope as Bearer token.
+func (h *handler) apiAuth(scope database.APIKeyScope, next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "Missing API key", http.StatusUnauthorized)
+			return
+		}
+
+		key, err := h.DB.GetAPIKeyByHash(ctx, server.HashAPIKey(token))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, "Invalid API key", http.StatusUnauthorized)
+				return
+			}
+			slog.ErrorContext(ctx, "Failed to get API key", slog.Any("err", err))
+			http.Error(w, "Failed to get API key: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if !slices.Contains(key.Scopes, string(scope)) {
+			http.Error(w, "API key is missing the "+string(scope)+" scope", http.StatusForbidden)
+			return
+		}
+
+		if !h.APIKeyLimiter.Allow(*key) {
+			http.Error(w, "Too many requests, please try again later", http.StatusTooManyRequests)
+			return
+		}
+
+		if err = h.DB.UpdateAPIKeyLastUsed(ctx, key.ID); err != nil {
+			slog.ErrorContext(ctx, "Failed to update API key last used", slog.Int("api_key_id", key.ID), slog.Any("err", err))
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(ctx, apiKeyContextKey, *key)))
+	})
+}
+
+func getAPIKey(r *http.Request) database.APIKey {
+	return r.Context().Value(apiKeyContextKey).(database.APIKey)
+}
+
+// apiKeyAllowsClub reports whether the API key may access the club. Keys without club restrictions may access all clubs.
+func apiKeyAllowsClub(key database.APIKey, clubID string) bool {
+	return len(key.ClubIDs) == 0 || slices.Contains(key.ClubIDs, clubID)
+}
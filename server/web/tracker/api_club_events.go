@@ -48,6 +48,11 @@ func (h *handler) APIClubEvents(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !apiKeyAllowsClub(getAPIKey(r), clubID) {
+		http.Error(w, "API key is not allowed to access this club", http.StatusForbidden)
+		return
+	}
+
 	if upcoming {
 		h.apiClubUpcomingEvents(w, r, clubID)
 		return
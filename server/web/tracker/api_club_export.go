@@ -0,0 +1,85 @@
+package tracker
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/topi314/campfire-tools/server/database"
+)
+
+// APIClubExport streams the club's imported events matching the filters from the database as a JSON array.
+func (h *handler) APIClubExport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	clubID := r.PathValue("club_id")
+
+	slog.InfoContext(ctx, "Received API club export request", slog.String("url", r.URL.String()), slog.String("club_id", clubID))
+
+	if !apiKeyAllowsClub(getAPIKey(r), clubID) {
+		http.Error(w, "API key is not allowed to access this club", http.StatusForbidden)
+		return
+	}
+
+	allCategories, err := h.getEventCategories(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get event categories", slog.Any("err", err))
+		http.Error(w, "Failed to get event categories", http.StatusInternalServerError)
+		return
+	}
+
+	filter, err := parseDatabaseExportFilter(clubID, r.URL.Query(), allCategories)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	club, err := h.DB.GetClub(ctx, clubID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Club not found", http.StatusNotFound)
+			return
+		}
+		slog.ErrorContext(ctx, "Failed to get club", slog.String("club_id", clubID), slog.Any("err", err))
+		http.Error(w, "Failed to get club: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var (
+		enc    = json.NewEncoder(w)
+		events int
+	)
+	err = h.streamDatabaseExport(ctx, filter, func(rsvps []database.EventRSVPExport) error {
+		separator := ","
+		if events == 0 {
+			w.Header().Set("Content-Type", "application/json")
+			separator = "["
+		}
+		events++
+		if _, err := fmt.Fprint(w, separator); err != nil {
+			return err
+		}
+		return enc.Encode(newDatabaseExportEvent(*club, rsvps))
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to export events from database", slog.String("club_id", clubID), slog.Any("err", err))
+		if events == 0 {
+			http.Error(w, "Failed to export events: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if events == 0 {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, "[")
+	}
+	if _, err = fmt.Fprintln(w, "]"); err != nil {
+		slog.ErrorContext(ctx, "Failed to write club export", slog.String("club_id", clubID), slog.Any("err", err))
+		return
+	}
+
+	slog.InfoContext(ctx, "Club export completed successfully", slog.String("club_id", clubID), slog.Int("events", events))
+}
@@ -3,17 +3,42 @@ package tracker
 import (
 	"log/slog"
 	"net/http"
+	"strings"
+
+	"github.com/topi314/campfire-tools/internal/openapi"
 )
 
 type APIDocsVars struct {
-	BaseURL string
+	BaseURL   string
+	Spec      openapi.Document
+	Endpoints []APIDocsEndpoint
+}
+
+type APIDocsEndpoint struct {
+	ID     string
+	Method string
+	Path   string
+	*openapi.Operation
 }
 
 func (h *handler) APIDocs(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	spec := h.openAPI()
+	endpoints := make([]APIDocsEndpoint, 0, len(apiRoutes))
+	for _, route := range apiRoutes {
+		endpoints = append(endpoints, APIDocsEndpoint{
+			ID:        route.OperationID,
+			Method:    route.Method,
+			Path:      route.Path,
+			Operation: spec.Paths[route.Path][strings.ToLower(route.Method)],
+		})
+	}
+
 	if err := h.Templates().ExecuteTemplate(w, "tracker_api_docs.gohtml", APIDocsVars{
-		BaseURL: h.Cfg.Server.PublicTrackerURL,
+		BaseURL:   h.Cfg.Server.PublicTrackerURL,
+		Spec:      spec,
+		Endpoints: endpoints,
 	}); err != nil {
 		slog.ErrorContext(ctx, "Failed to render API docs template", slog.Any("err", err))
 		return
@@ -64,6 +64,13 @@ func (h *handler) APIExportEvents(w http.ResponseWriter, r *http.Request) {
 
 	slog.InfoContext(ctx, "Received API events request", slog.String("url", r.URL.String()), slog.Any("events", events))
 
+	// the club of an event is only known after fetching it with all its members from Campfire,
+	// so keys restricted to clubs could burn the rate limit with events of other clubs
+	if key := getAPIKey(r); len(key.ClubIDs) > 0 {
+		http.Error(w, "API keys restricted to clubs can not export events by ID, use the club endpoints instead", http.StatusForbidden)
+		return
+	}
+
 	if len(events) == 0 {
 		http.Error(w, "Missing or empty 'events' parameter", http.StatusBadRequest)
 		return
@@ -12,6 +12,13 @@ func (h *handler) APIImportEvents(w http.ResponseWriter, r *http.Request) {
 
 	slog.InfoContext(ctx, "Received API import events request", slog.String("url", r.URL.String()))
 
+	// the club of an event is only known after fetching it with all its members from Campfire,
+	// so keys restricted to clubs could burn the rate limit with events of other clubs
+	if key := getAPIKey(r); len(key.ClubIDs) > 0 {
+		http.Error(w, "API keys restricted to clubs can not import events", http.StatusForbidden)
+		return
+	}
+
 	var events []string
 	if err := json.NewDecoder(r.Body).Decode(&events); err != nil {
 		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
@@ -23,7 +30,14 @@ func (h *handler) APIImportEvents(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.importAllEvents(ctx, events); err != nil {
+	campfireEvents, err := h.fetchImportEvents(ctx, events)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch events for import", slog.Any("error", err))
+		http.Error(w, "Failed to import events: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err = h.bulkProcessEvents(ctx, campfireEvents); err != nil {
 		slog.ErrorContext(ctx, "Failed to import events", slog.Any("error", err))
 		http.Error(w, "Failed to import events: "+err.Error(), http.StatusInternalServerError)
 		return
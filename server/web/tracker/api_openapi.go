@@ -0,0 +1,238 @@
+package tracker
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/topi314/campfire-tools/internal/openapi"
+	"github.com/topi314/campfire-tools/server/database"
+)
+
+const apiSecurityScheme = "apiKey"
+
+// apiRoute is an endpoint of the public API. Routes are registered and documented in the OpenAPI spec from the same definition.
+type apiRoute struct {
+	Method      string
+	Path        string
+	OperationID string
+	Summary     string
+	Description string
+	// Scope is the scope the API key needs. Endpoints without a scope do not require an API key and authorize requests themselves.
+	Scope      database.APIKeyScope
+	Parameters []openapi.Parameter
+	// RequestBody is the type of the JSON request body, nil if the endpoint has none.
+	RequestBody reflect.Type
+	// Responses are the types of the JSON response body, an endpoint without any responds with 204 No Content.
+	Responses []reflect.Type
+	// ResponseContentType is the content type of an endpoint which responds with a text body instead of JSON.
+	ResponseContentType string
+	Handler             func(h *handler, w http.ResponseWriter, r *http.Request)
+}
+
+var apiRoutes = []apiRoute{
+	{
+		Method:      http.MethodGet,
+		Path:        "/api/events",
+		OperationID: "exportEvents",
+		Summary:     "Events Export",
+		Description: "Return events with check-ins from Campfire. API keys restricted to clubs can not export events by ID, they can use the club endpoints instead.",
+		Scope:       database.APIKeyScopeEventsRead,
+		Parameters: []openapi.Parameter{
+			{Name: "events", In: "query", Required: true, Description: "Comma-separated list of event links/IDs to return", Schema: &openapi.Schema{Type: "string"}},
+		},
+		Responses: []reflect.Type{reflect.TypeFor[[]ExportEvent]()},
+		Handler:   (*handler).APIExportEvents,
+	},
+	{
+		Method:      http.MethodPost,
+		Path:        "/api/events",
+		OperationID: "importEvents",
+		Summary:     "Events Import",
+		Description: "Import events from Campfire. API keys restricted to clubs can not import events.",
+		Scope:       database.APIKeyScopeEventsImport,
+		RequestBody: reflect.TypeFor[[]string](),
+		Handler:     (*handler).APIImportEvents,
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/api/clubs/{club_id}/events",
+		OperationID: "getClubEvents",
+		Summary:     "Club Events",
+		Description: "Return a club's imported events with check-ins from Campfire. With upcoming=true only upcoming and currently running events are returned, without members but with accepted and checked in counts.",
+		Scope:       database.APIKeyScopeClubsRead,
+		Parameters: []openapi.Parameter{
+			{Name: "club_id", In: "path", Required: true, Description: "The ID of the club to return events for", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "upcoming", In: "query", Description: "Return only upcoming and currently running events. Defaults to false.", Schema: &openapi.Schema{Type: "boolean"}},
+			{Name: "timezone", In: "query", Description: "IANA timezone name used to convert event times when upcoming=true. Defaults to UTC.", Schema: &openapi.Schema{Type: "string"}},
+		},
+		Responses: []reflect.Type{reflect.TypeFor[[]ExportEvent](), reflect.TypeFor[[]UpcomingClubEvent]()},
+		Handler:   (*handler).APIClubEvents,
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/api/clubs/{club_id}/export",
+		OperationID: "exportClubEvents",
+		Summary:     "Club Events Export",
+		Description: "Return a club's imported events with their RSVPs straight from the database, without fetching them from Campfire. The events are streamed, so there is no limit on how many are returned. Events without RSVPs are skipped and badges are not included.",
+		Scope:       database.APIKeyScopeClubsRead,
+		Parameters: []openapi.Parameter{
+			{Name: "club_id", In: "path", Required: true, Description: "The ID of the club to export events for", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "from", In: "query", Description: "Only return events starting on or after this date.", Schema: &openapi.Schema{Type: "string", Format: "date"}},
+			{Name: "to", In: "query", Description: "Only return events starting on or before this date.", Schema: &openapi.Schema{Type: "string", Format: "date"}},
+			{Name: "only-ca-events", In: "query", Description: "Only return events created by community ambassadors. Defaults to false.", Schema: &openapi.Schema{Type: "boolean"}},
+			{Name: "event-creator", In: "query", Description: "Only return events created by this member ID.", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "category", In: "query", Description: "Only return events of this live event category, for example Community Day, Other or No Event. Can be repeated.", Schema: &openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "string"}}},
+		},
+		Responses: []reflect.Type{reflect.TypeFor[[]ExportEvent]()},
+		Handler:   (*handler).APIClubExport,
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/api/clubs/{club_id}/calendar.ics",
+		OperationID: "getClubCalendar",
+		Summary:     "Club Calendar",
+		Description: "Return the events of a club from the last year and all upcoming events as iCalendar feed. It does not require an API key, but the calendar token of the club, which can be created in the club settings. A missing or wrong token returns 404.",
+		Parameters: []openapi.Parameter{
+			{Name: "club_id", In: "path", Required: true, Description: "The ID of the club to return the calendar for", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "token", In: "query", Required: true, Description: "The calendar token of the club", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "only-ca-events", In: "query", Description: "Only return events created by community ambassadors. Defaults to false.", Schema: &openapi.Schema{Type: "boolean"}},
+			{Name: "category", In: "query", Description: "Only return events of this live event category, for example Community Day, Other or No Event. Can be repeated.", Schema: &openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "string"}}},
+		},
+		ResponseContentType: "text/calendar",
+		Handler:             (*handler).APIClubCalendar,
+	},
+}
+
+func (h *handler) registerAPIRoutes(mux *http.ServeMux) {
+	for _, route := range apiRoutes {
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			route.Handler(h, w, r)
+		}
+		if route.Scope == "" {
+			mux.HandleFunc(route.Method+" "+route.Path, handler)
+			continue
+		}
+		mux.Handle(route.Method+" "+route.Path, h.apiAuth(route.Scope, handler))
+	}
+}
+
+func (h *handler) openAPI() openapi.Document {
+	g := openapi.NewGenerator()
+	textResponse := func(description string) openapi.Response {
+		return openapi.Response{
+			Description: description,
+			Content: map[string]openapi.MediaType{
+				"text/plain": {Schema: &openapi.Schema{Type: "string"}},
+			},
+		}
+	}
+
+	paths := make(map[string]openapi.PathItem)
+	for _, route := range apiRoutes {
+		operation := &openapi.Operation{
+			OperationID: route.OperationID,
+			Summary:     route.Summary,
+			Description: route.Description,
+			Parameters:  route.Parameters,
+			Responses: map[string]openapi.Response{
+				"400": textResponse("Invalid request"),
+				"500": textResponse("Internal server error"),
+			},
+		}
+
+		if route.Scope != "" {
+			operation.Responses["401"] = textResponse("Missing or invalid API key")
+			operation.Responses["403"] = textResponse("API key is missing the scope or is not allowed to access the club")
+			operation.Responses["429"] = textResponse("Rate limit of the API key exceeded")
+			operation.Security = []map[string][]string{
+				{apiSecurityScheme: {string(route.Scope)}},
+			}
+		} else {
+			operation.Responses["404"] = openapi.Response{Description: "Not found or invalid token"}
+		}
+
+		if route.RequestBody != nil {
+			operation.RequestBody = &openapi.RequestBody{
+				Required: true,
+				Content: map[string]openapi.MediaType{
+					"application/json": {Schema: g.Schema(route.RequestBody)},
+				},
+			}
+		}
+
+		switch len(route.Responses) {
+		case 0:
+			if route.ResponseContentType != "" {
+				operation.Responses[strconv.Itoa(http.StatusOK)] = openapi.Response{
+					Description: "OK",
+					Content: map[string]openapi.MediaType{
+						route.ResponseContentType: {Schema: &openapi.Schema{Type: "string"}},
+					},
+				}
+				break
+			}
+			operation.Responses[strconv.Itoa(http.StatusNoContent)] = openapi.Response{Description: "No Content"}
+		case 1:
+			operation.Responses[strconv.Itoa(http.StatusOK)] = openapi.Response{
+				Description: "OK",
+				Content: map[string]openapi.MediaType{
+					"application/json": {Schema: g.Schema(route.Responses[0])},
+				},
+			}
+		default:
+			schema := &openapi.Schema{}
+			for _, response := range route.Responses {
+				schema.OneOf = append(schema.OneOf, g.Schema(response))
+			}
+			operation.Responses[strconv.Itoa(http.StatusOK)] = openapi.Response{
+				Description: "OK",
+				Content: map[string]openapi.MediaType{
+					"application/json": {Schema: schema},
+				},
+			}
+		}
+
+		if paths[route.Path] == nil {
+			paths[route.Path] = make(openapi.PathItem)
+		}
+		paths[route.Path][strings.ToLower(route.Method)] = operation
+	}
+
+	return openapi.Document{
+		OpenAPI: openapi.Version,
+		Info: openapi.Info{
+			Title:       "Campfire Tools API",
+			Description: "Export and import Campfire events and clubs.",
+			Version:     "1.0.0",
+		},
+		Servers: []openapi.Server{
+			{URL: h.Cfg.Server.PublicTrackerURL},
+		},
+		Paths: paths,
+		Components: openapi.Components{
+			Schemas: g.Schemas(),
+			SecuritySchemes: map[string]openapi.SecurityScheme{
+				apiSecurityScheme: {
+					Type:        "http",
+					Scheme:      "bearer",
+					Description: "API key created in the tracker settings, sent as `Authorization: Bearer <key>`.",
+				},
+			},
+		},
+	}
+}
+
+func (h *handler) APIOpenAPI(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(h.openAPI()); err != nil {
+		slog.ErrorContext(ctx, "Failed to encode OpenAPI document", slog.Any("err", err))
+	}
+}
@@ -0,0 +1,50 @@
+package tracker
+
+import (
+	"bytes"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/topi314/campfire-tools/server"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// TestAPIOpenAPI fails on any change of the generated OpenAPI spec. Run it with -update after intended API changes.
+func TestAPIOpenAPI(t *testing.T) {
+	h := &handler{Server: &server.Server{
+		Cfg: server.Config{
+			Server: server.ServerConfig{PublicTrackerURL: "https://tracker.example.com"},
+		},
+	}}
+
+	rec := httptest.NewRecorder()
+	h.APIOpenAPI(rec, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status code: %d", rec.Code)
+	}
+	if contentType := rec.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Fatalf("unexpected content type: %q", contentType)
+	}
+
+	golden := filepath.Join("testdata", "openapi.json")
+	if *update {
+		if err := os.WriteFile(golden, rec.Body.Bytes(), 0o644); err != nil {
+			t.Fatalf("failed to update golden file: %s", err)
+		}
+	}
+
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("failed to read golden file: %s", err)
+	}
+
+	if !bytes.Equal(rec.Body.Bytes(), expected) {
+		t.Errorf("generated OpenAPI spec does not match %s, run the test with -update if the change is intended:\n%s", golden, rec.Body.String())
+	}
+}
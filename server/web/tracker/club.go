@@ -14,8 +14,10 @@ import (
 
 type TrackerClubVars struct {
 	models.Club
-	Events []models.Event
-	Pinned bool
+	Events             []models.Event
+	Pinned             bool
+	CalendarURL        string
+	CalendarCategories []string
 }
 
 func (h *handler) TrackerClub(w http.ResponseWriter, r *http.Request) {
@@ -57,10 +59,19 @@ func (h *handler) TrackerClub(w http.ResponseWriter, r *http.Request) {
 	}
 	pinned := slices.Contains(pinnedClubs, clubID)
 
+	allCategories, err := h.getEventCategories(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch event categories", slog.Any("err", err))
+		http.Error(w, "Failed to fetch event categories: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	if err = h.Templates().ExecuteTemplate(w, "tracker_club.gohtml", TrackerClubVars{
-		Club:   clubModel,
-		Events: trackerEvents,
-		Pinned: pinned,
+		Club:               clubModel,
+		Events:             trackerEvents,
+		Pinned:             pinned,
+		CalendarURL:        h.calendarURL(club.Club),
+		CalendarCategories: allCategories.filterNames(),
 	}); err != nil {
 		slog.ErrorContext(ctx, "Failed to render tracker club template", slog.String("club_id", clubID), slog.Any("err", err))
 	}
@@ -0,0 +1,194 @@
+package tracker
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/topi314/campfire-tools/server/web/models"
+)
+
+const attendanceLocationsLimit = 50
+
+// attendanceWeekdays are the weekdays of the heatmap, starting with Monday.
+var attendanceWeekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday}
+
+type Attendance struct {
+	Open      bool
+	TimeZone  string
+	Hours     []int
+	Weekdays  []AttendanceWeekday
+	Locations []AttendanceLocation
+}
+
+type AttendanceWeekday struct {
+	Weekday time.Weekday
+	Slots   []AttendanceSlot
+}
+
+// AttendanceSlot are the events starting within an hour of a weekday.
+// Heat is the average check-ins relative to the best slot, between 0 and 1.
+type AttendanceSlot struct {
+	Hour        int
+	Events      int
+	Accepted    int
+	CheckIns    int
+	AvgCheckIns float64
+	Heat        float64
+}
+
+// AttendanceLocation are the events at the same location and address with the slot which had the most average check-ins there.
+type AttendanceLocation struct {
+	Location    string
+	Address     string
+	Events      int
+	Accepted    int
+	CheckIns    int
+	AvgAccepted float64
+	AvgCheckIns float64
+	CheckInRate float64
+	BestWeekday time.Weekday
+	BestHour    int
+	BestAvg     float64
+}
+
+type attendanceSlotKey struct {
+	weekday time.Weekday
+	hour    int
+}
+
+type attendanceTotals struct {
+	events   int
+	accepted int
+	checkIns int
+}
+
+func (h *handler) calculateAttendance(ctx context.Context, clubID string, from time.Time, to time.Time, onlyCAEvents bool, eventCreator string, loc *time.Location, attendanceClosed bool) (*Attendance, error) {
+	events, err := h.DB.GetEvents(ctx, clubID, from, to, onlyCAEvents, eventCreator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch events: %w", err)
+	}
+
+	now := time.Now()
+	slots := make(map[attendanceSlotKey]attendanceTotals)
+	locations := make(map[[2]string]*AttendanceLocation)
+	locationSlots := make(map[[2]string]map[attendanceSlotKey]attendanceTotals)
+	firstHour, lastHour := 24, -1
+	for _, event := range events {
+		// events which did not end yet can still get check-ins
+		if !event.Finished && event.EndTime.After(now) {
+			continue
+		}
+
+		eventTime := event.Time.In(loc)
+		key := attendanceSlotKey{weekday: eventTime.Weekday(), hour: eventTime.Hour()}
+		slot := slots[key]
+		slot.events++
+		slot.accepted += event.Accepted
+		slot.checkIns += event.CheckIns
+		slots[key] = slot
+		firstHour = min(firstHour, key.hour)
+		lastHour = max(lastHour, key.hour)
+
+		locationKey := [2]string{event.Location, event.Address}
+		location, ok := locations[locationKey]
+		if !ok {
+			location = &AttendanceLocation{
+				Location: event.Location,
+				Address:  event.Address,
+			}
+			locations[locationKey] = location
+			locationSlots[locationKey] = make(map[attendanceSlotKey]attendanceTotals)
+		}
+		location.Events++
+		location.Accepted += event.Accepted
+		location.CheckIns += event.CheckIns
+
+		locationSlot := locationSlots[locationKey][key]
+		locationSlot.events++
+		locationSlot.checkIns += event.CheckIns
+		locationSlots[locationKey][key] = locationSlot
+	}
+
+	var hours []int
+	for hour := firstHour; hour <= lastHour; hour++ {
+		hours = append(hours, hour)
+	}
+
+	var bestAvg float64
+	for _, slot := range slots {
+		bestAvg = max(bestAvg, models.CalcAverage(slot.checkIns, slot.events))
+	}
+
+	weekdays := make([]AttendanceWeekday, len(attendanceWeekdays))
+	for i, weekday := range attendanceWeekdays {
+		weekdays[i] = AttendanceWeekday{
+			Weekday: weekday,
+			Slots:   make([]AttendanceSlot, len(hours)),
+		}
+		for j, hour := range hours {
+			slot := slots[attendanceSlotKey{weekday: weekday, hour: hour}]
+			avgCheckIns := models.CalcAverage(slot.checkIns, slot.events)
+			weekdays[i].Slots[j] = AttendanceSlot{
+				Hour:        hour,
+				Events:      slot.events,
+				Accepted:    slot.accepted,
+				CheckIns:    slot.checkIns,
+				AvgCheckIns: avgCheckIns,
+			}
+			if bestAvg > 0 {
+				weekdays[i].Slots[j].Heat = math.Round(avgCheckIns/bestAvg*100) / 100
+			}
+		}
+	}
+
+	attendanceLocations := make([]AttendanceLocation, 0, len(locations))
+	for locationKey, location := range locations {
+		location.AvgAccepted = models.CalcAverage(location.Accepted, location.Events)
+		location.AvgCheckIns = models.CalcAverage(location.CheckIns, location.Events)
+		location.CheckInRate = models.CalcCheckInRate(location.Accepted, location.CheckIns)
+
+		for key, slot := range locationSlots[locationKey] {
+			avgCheckIns := models.CalcAverage(slot.checkIns, slot.events)
+			if avgCheckIns > location.BestAvg || (avgCheckIns == location.BestAvg && slotBefore(key, location.BestWeekday, location.BestHour)) {
+				location.BestWeekday = key.weekday
+				location.BestHour = key.hour
+				location.BestAvg = avgCheckIns
+			}
+		}
+
+		attendanceLocations = append(attendanceLocations, *location)
+	}
+	slices.SortFunc(attendanceLocations, func(a, b AttendanceLocation) int {
+		return cmp.Or(
+			cmp.Compare(b.AvgCheckIns, a.AvgCheckIns),
+			cmp.Compare(b.Events, a.Events),
+			cmp.Compare(a.Location, b.Location),
+			cmp.Compare(a.Address, b.Address),
+		)
+	})
+	if len(attendanceLocations) > attendanceLocationsLimit {
+		attendanceLocations = attendanceLocations[:attendanceLocationsLimit]
+	}
+
+	return &Attendance{
+		Open:      !attendanceClosed,
+		TimeZone:  loc.String(),
+		Hours:     hours,
+		Weekdays:  weekdays,
+		Locations: attendanceLocations,
+	}, nil
+}
+
+// slotBefore reports whether the slot comes before the weekday and hour in the week, so ties of the best slot do not depend on map order.
+func slotBefore(key attendanceSlotKey, weekday time.Weekday, hour int) bool {
+	i := slices.Index(attendanceWeekdays, key.weekday)
+	j := slices.Index(attendanceWeekdays, weekday)
+	if i != j {
+		return i < j
+	}
+	return key.hour < hour
+}
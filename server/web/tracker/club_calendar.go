@@ -0,0 +1,135 @@
+package tracker
+
+import (
+	"cmp"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/topi314/campfire-tools/internal/ical"
+	"github.com/topi314/campfire-tools/internal/xquery"
+	"github.com/topi314/campfire-tools/internal/xrand"
+	"github.com/topi314/campfire-tools/server/campfire"
+	"github.com/topi314/campfire-tools/server/database"
+)
+
+// calendarHistory is how far back past events are included in the calendar feed.
+const calendarHistory = 365 * 24 * time.Hour
+
+func (h *handler) calendarURL(club database.Club) string {
+	if club.CalendarToken == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s/api/clubs/%s/calendar.ics?%s", h.Cfg.Server.PublicTrackerURL, club.ID, url.Values{"token": {*club.CalendarToken}}.Encode())
+}
+
+func (h *handler) APIClubCalendar(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	clubID := r.PathValue("club_id")
+	token := query.Get("token")
+	onlyCAEvents := xquery.ParseBool(query, "only-ca-events", false)
+
+	allCategories, err := h.getEventCategories(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get event categories", slog.Any("err", err))
+		http.Error(w, "Failed to get event categories", http.StatusInternalServerError)
+		return
+	}
+
+	categories, err := allCategories.parse(query["category"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	club, err := h.DB.GetClub(ctx, clubID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			h.NotFound(w, r)
+			return
+		}
+		slog.ErrorContext(ctx, "Failed to get club", slog.String("club_id", clubID), slog.Any("err", err))
+		http.Error(w, "Failed to get club", http.StatusInternalServerError)
+		return
+	}
+
+	// do not reveal whether the club exists or has a calendar
+	if club.CalendarToken == nil || subtle.ConstantTimeCompare([]byte(token), []byte(*club.CalendarToken)) != 1 {
+		h.NotFound(w, r)
+		return
+	}
+
+	events, err := h.DB.GetEvents(ctx, clubID, time.Now().Add(-calendarHistory), time.Time{}, onlyCAEvents, "")
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get events for club", slog.String("club_id", clubID), slog.Any("err", err))
+		http.Error(w, "Failed to get events", http.StatusInternalServerError)
+		return
+	}
+
+	calendar := ical.Calendar{
+		ProdID: "-//Campfire Tools//Club Calendar//EN",
+		Name:   club.Club.Name,
+	}
+	for _, event := range events {
+		if len(categories) > 0 && !slices.Contains(categories, allCategories.fromName(event.CampfireLiveEventName)) {
+			continue
+		}
+
+		eventURL := campfire.EventURL(event.ID)
+		description := eventURL
+		if event.CampfireLiveEventName != "" {
+			description = event.CampfireLiveEventName + "\n\n" + description
+		}
+		if event.Details != "" {
+			description = event.Details + "\n\n" + description
+		}
+
+		calendar.Events = append(calendar.Events, ical.Event{
+			UID:         event.ID + "@campfire-tools",
+			Summary:     event.Name,
+			Description: description,
+			Location:    cmp.Or(event.Address, event.Location),
+			URL:         eventURL,
+			Start:       event.Time,
+			End:         event.EndTime,
+		})
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="%s.ics"`, clubID))
+	if err = calendar.Encode(w); err != nil {
+		slog.ErrorContext(ctx, "Failed to encode club calendar", slog.String("club_id", clubID), slog.Any("err", err))
+	}
+}
+
+// TrackerClubCalendarToken creates a new calendar token for the club, which invalidates the previous feed URL.
+func (h *handler) TrackerClubCalendarToken(w http.ResponseWriter, r *http.Request) {
+	token := xrand.NewSeed()
+	h.updateClubCalendarToken(w, r, &token)
+}
+
+func (h *handler) TrackerClubCalendarTokenDelete(w http.ResponseWriter, r *http.Request) {
+	h.updateClubCalendarToken(w, r, nil)
+}
+
+func (h *handler) updateClubCalendarToken(w http.ResponseWriter, r *http.Request, token *string) {
+	ctx := r.Context()
+
+	clubID := r.PathValue("club_id")
+
+	if err := h.DB.UpdateClubCalendarToken(ctx, clubID, token); err != nil {
+		slog.ErrorContext(ctx, "Failed to update club calendar token", slog.String("club_id", clubID), slog.Any("err", err))
+		http.Error(w, "Failed to update club calendar token", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/tracker/club/%s", clubID), http.StatusSeeOther)
+}
@@ -0,0 +1,62 @@
+package tracker
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/topi314/campfire-tools/server/web/models"
+)
+
+type TrackerClubEventTimelineVars struct {
+	models.Event
+	models.RSVPTimeline
+
+	Club models.Club
+}
+
+func (h *handler) TrackerClubEventTimeline(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	eventID := r.PathValue("event_id")
+
+	event, err := h.DB.GetEvent(ctx, eventID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			h.NotFound(w, r)
+			return
+		}
+		slog.ErrorContext(ctx, "Failed to fetch event", slog.String("event_id", eventID), slog.Any("err", err))
+		http.Error(w, "Failed to fetch event: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	club, err := h.DB.GetClub(ctx, event.ClubID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			h.NotFound(w, r)
+			return
+		}
+		slog.ErrorContext(ctx, "Failed to fetch club", slog.String("club_id", event.ClubID), slog.Any("err", err))
+		http.Error(w, "Failed to fetch club: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	history, err := h.DB.GetEventRSVPHistory(ctx, eventID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch event RSVP history", slog.String("event_id", eventID), slog.Any("err", err))
+		http.Error(w, "Failed to fetch event RSVP history: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	clubModel := models.NewClub(*club)
+
+	if err = h.Templates().ExecuteTemplate(w, "tracker_club_event_timeline.gohtml", TrackerClubEventTimelineVars{
+		Event:        models.NewEventWithCreator(*event, clubModel.AvatarURL),
+		RSVPTimeline: models.NewRSVPTimeline(event.Event, history),
+		Club:         clubModel,
+	}); err != nil {
+		slog.ErrorContext(ctx, "Failed to render tracker club event timeline template", slog.String("event_id", eventID), slog.Any("err", err))
+	}
+}
@@ -1,31 +1,66 @@
 package tracker
 
 import (
+	"fmt"
 	"log/slog"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/topi314/campfire-tools/internal/xquery"
 	"github.com/topi314/campfire-tools/internal/xtime"
+	"github.com/topi314/campfire-tools/server"
+	"github.com/topi314/campfire-tools/server/auth"
+	"github.com/topi314/campfire-tools/server/database"
 	"github.com/topi314/campfire-tools/server/web/models"
 )
 
+// EventCategoryOption is an option of the event categories select.
+type EventCategoryOption struct {
+	Name     string
+	Selected bool
+}
+
+func newEventCategoryOptions(allCategories eventCategories, selected []string) []EventCategoryOption {
+	categories := allCategories.filterNames()
+	options := make([]EventCategoryOption, len(categories))
+	for i, category := range categories {
+		options[i] = EventCategoryOption{
+			Name:     category,
+			Selected: slices.Contains(selected, category),
+		}
+	}
+	return options
+}
+
 type TrackerClubExportVars struct {
 	models.Club
 	EventsFilter
 
-	Events          []models.Event
-	SelectedEventID string
-	Error           string
+	ExportOptions
+
+	Events           []models.Event
+	SelectedEventID  string
+	Categories       []EventCategoryOption
+	Presets          []models.ExportPreset
+	SelectedPresetID int
+	EventRanges      []ExportEventRange
+	Error            string
 }
 
 func (h *handler) TrackerClubExport(w http.ResponseWriter, r *http.Request) {
-	h.renderTrackerClubExport(w, r, "")
+	if presetID := r.URL.Query().Get("preset"); presetID != "" {
+		h.runTrackerClubExportPreset(w, r, presetID)
+		return
+	}
+	h.renderTrackerClubExport(w, r, nil, "")
 }
 
-func (h *handler) renderTrackerClubExport(w http.ResponseWriter, r *http.Request, errorMessage string) {
+// renderTrackerClubExport renders the export form, filled in with the options of the preset if one is given.
+func (h *handler) renderTrackerClubExport(w http.ResponseWriter, r *http.Request, preset *database.ExportPreset, errorMessage string) {
 	ctx := r.Context()
 	query := r.URL.Query()
+	session := auth.GetSession(r)
 
 	clubID := r.PathValue("club_id")
 	eventID := query.Get("event")
@@ -66,6 +101,31 @@ func (h *handler) renderTrackerClubExport(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	presets, err := h.DB.GetExportPresets(ctx, session.UserID, clubID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch export presets", slog.String("club_id", clubID), slog.Any("err", err))
+		http.Error(w, "Failed to fetch export presets: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	allCategories, err := h.getEventCategories(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch event categories", slog.Any("err", err))
+		http.Error(w, "Failed to fetch event categories: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	options := defaultExportOptions()
+	var (
+		selectedCategories []string
+		selectedPresetID   int
+	)
+	if preset != nil {
+		options = exportPresetOptions(*preset)
+		selectedCategories = preset.Categories
+		selectedPresetID = preset.ID
+	}
+
 	if err = h.Templates().ExecuteTemplate(w, "tracker_club_export.gohtml", TrackerClubExportVars{
 		Club: clubModel,
 		EventsFilter: EventsFilter{
@@ -77,10 +137,89 @@ func (h *handler) renderTrackerClubExport(w http.ResponseWriter, r *http.Request
 			EventCreators:        eventCreators,
 			SelectedEventCreator: eventCreator,
 		},
-		Events:          trackerEvents,
-		SelectedEventID: eventID,
-		Error:           errorMessage,
+		ExportOptions:    options,
+		Events:           trackerEvents,
+		SelectedEventID:  eventID,
+		Categories:       newEventCategoryOptions(allCategories, selectedCategories),
+		Presets:          newExportPresets(presets),
+		SelectedPresetID: selectedPresetID,
+		EventRanges:      exportEventRanges,
+		Error:            errorMessage,
 	}); err != nil {
 		slog.ErrorContext(ctx, "Failed to render tracker club export template", slog.Any("err", err))
 	}
 }
+
+// DoTrackerClubDatabaseExport exports the club's imported events matching the filters straight from the database.
+func (h *handler) DoTrackerClubDatabaseExport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := r.ParseForm(); err != nil {
+		slog.ErrorContext(ctx, "Failed to parse form", slog.Any("err", err))
+		http.Error(w, "Failed to parse form: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	allCategories, err := h.getEventCategories(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch event categories", slog.Any("err", err))
+		http.Error(w, "Failed to fetch event categories: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	filter, err := parseDatabaseExportFilter(r.PathValue("club_id"), r.Form, allCategories)
+	if err != nil {
+		h.renderTrackerClubExport(w, r, nil, err.Error())
+		return
+	}
+	options, err := parseExportOptions(r.Form)
+	if err != nil {
+		h.renderTrackerClubExport(w, r, nil, err.Error())
+		return
+	}
+
+	h.exportDatabase(w, r, filter, options, nil)
+}
+
+// exportDatabase writes the events matching the filter from the database while they are read, so there is no limit on the number of events.
+// Errors before anything was written are shown in the export form of the preset, if the export was started from one.
+func (h *handler) exportDatabase(w http.ResponseWriter, r *http.Request, filter databaseExportFilter, options ExportOptions, preset *database.ExportPreset) {
+	ctx := r.Context()
+
+	slog.InfoContext(ctx, "Received database export request",
+		slog.String("club_id", filter.ClubID),
+		slog.Time("from", filter.From),
+		slog.Time("to", filter.To),
+		slog.Bool("only_ca_events", filter.OnlyCAEvents),
+		slog.String("event_creator", filter.EventCreator),
+		slog.Any("categories", filter.Categories),
+		slog.Bool("include_missing_members", options.IncludeMissingMembers),
+		slog.Bool("combine_csv", options.CombineCSVs),
+		slog.Any("included_fields", options.IncludedFields),
+		slog.String("format", options.Format),
+	)
+
+	ew := newDatabaseExportWriter(w, options, func(file server.ExportFile) {
+		w.Header().Set("Content-Type", file.ContentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.%s", exportName(), file.Extension))
+	})
+	if err := h.streamDatabaseExport(ctx, filter, ew.addEvent); err != nil {
+		slog.ErrorContext(ctx, "Failed to export events from database", slog.String("club_id", filter.ClubID), slog.Any("err", err))
+		if ew.events == 0 {
+			h.renderTrackerClubExport(w, r, preset, "Failed to export events: "+err.Error())
+		}
+		return
+	}
+
+	if ew.events == 0 {
+		h.renderTrackerClubExport(w, r, preset, "No events with RSVPs match the selected filters")
+		return
+	}
+
+	if err := ew.Close(); err != nil {
+		slog.ErrorContext(ctx, "Failed to finish database export", slog.Any("err", err))
+		return
+	}
+
+	slog.InfoContext(ctx, "Database export completed successfully", slog.String("club_id", filter.ClubID), slog.Int("events", ew.events))
+}
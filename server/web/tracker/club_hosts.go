@@ -0,0 +1,287 @@
+package tracker
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/topi314/campfire-tools/internal/xquery"
+	"github.com/topi314/campfire-tools/internal/xtime"
+	"github.com/topi314/campfire-tools/server/database"
+	"github.com/topi314/campfire-tools/server/web/models"
+)
+
+// hostTrendQuarters is the number of quarters shown in the trend of the hosts page.
+const hostTrendQuarters = 4
+
+type TrackerClubHostsVars struct {
+	models.Club
+	EventsFilter
+
+	Hosts         []Host
+	TrendQuarters []xtime.Quarter
+}
+
+type TrackerClubHostVars struct {
+	models.Club
+	EventsFilter
+
+	Host           Host
+	HostQuarters   []HostQuarter
+	Events         []models.TopEvent
+	EventsStatsURL string
+}
+
+// Host is a member who created events of a club.
+type Host struct {
+	models.Member
+	HostURL     string
+	Events      int
+	CAEvents    int
+	Accepted    int
+	CheckIns    int
+	AvgAccepted float64
+	AvgCheckIns float64
+	CheckInRate float64
+	CAShare     float64
+	Trend       []HostQuarter
+}
+
+// HostQuarter are the events a host created within a quarter.
+type HostQuarter struct {
+	xtime.Quarter
+	Events      int
+	Accepted    int
+	CheckIns    int
+	AvgAccepted float64
+	AvgCheckIns float64
+	CheckInRate float64
+}
+
+func (h *handler) TrackerClubHosts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	clubID := r.PathValue("club_id")
+	from := xquery.ParseTime(query, "from", time.Time{})
+	to := xquery.ParseTime(query, "to", time.Time{})
+	if !to.IsZero() {
+		to = to.Add(time.Hour*23 + time.Minute*59 + time.Second*59) // End of the day
+	}
+	onlyCAEvents := xquery.ParseBool(query, "only-ca-events", false)
+
+	club, err := h.DB.GetClub(ctx, clubID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			h.NotFound(w, r)
+			return
+		}
+		http.Error(w, "Failed to fetch club: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	hostStats, err := h.DB.GetClubHostStats(ctx, clubID, from, to, onlyCAEvents, "")
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch host stats for club", slog.String("club_id", clubID), slog.Any("err", err))
+		http.Error(w, "Failed to fetch host stats: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	quarters := xtime.GetQuarters()
+	if len(quarters) > hostTrendQuarters {
+		quarters = quarters[:hostTrendQuarters]
+	}
+	trendFrom, _ := xtime.GetRangeFromQuarter(quarters[len(quarters)-1].Value)
+
+	events, err := h.DB.GetEvents(ctx, clubID, trendFrom, time.Time{}, onlyCAEvents, "")
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch events for club", slog.String("club_id", clubID), slog.Any("err", err))
+		http.Error(w, "Failed to fetch events: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	trends := calculateHostQuarters(quarters, events)
+
+	hosts := make([]Host, len(hostStats))
+	for i, host := range hostStats {
+		hosts[i] = newHost(host, clubID)
+		hosts[i].Trend = trends[host.ID]
+		if hosts[i].Trend == nil {
+			hosts[i].Trend = newHostQuarters(quarters)
+		}
+	}
+
+	if err = h.Templates().ExecuteTemplate(w, "tracker_club_hosts.gohtml", TrackerClubHostsVars{
+		Club: models.NewClub(*club),
+		EventsFilter: EventsFilter{
+			FilterURL:    r.URL.Path,
+			From:         from,
+			To:           to,
+			OnlyCAEvents: onlyCAEvents,
+			Quarters:     xtime.GetQuarters(),
+		},
+		Hosts:         hosts,
+		TrendQuarters: quarters,
+	}); err != nil {
+		slog.ErrorContext(ctx, "Failed to render tracker club hosts template", slog.String("club_id", clubID), slog.Any("err", err))
+	}
+}
+
+func (h *handler) TrackerClubHost(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	clubID := r.PathValue("club_id")
+	memberID := r.PathValue("member_id")
+	from := xquery.ParseTime(query, "from", time.Time{})
+	to := xquery.ParseTime(query, "to", time.Time{})
+	if !to.IsZero() {
+		to = to.Add(time.Hour*23 + time.Minute*59 + time.Second*59) // End of the day
+	}
+	onlyCAEvents := xquery.ParseBool(query, "only-ca-events", false)
+
+	club, err := h.DB.GetClub(ctx, clubID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			h.NotFound(w, r)
+			return
+		}
+		http.Error(w, "Failed to fetch club: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	member, err := h.DB.GetMember(ctx, memberID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			h.NotFound(w, r)
+			return
+		}
+		slog.ErrorContext(ctx, "Failed to fetch member", slog.String("member_id", memberID), slog.Any("err", err))
+		http.Error(w, "Failed to fetch member: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	hostStats, err := h.DB.GetClubHostStats(ctx, clubID, from, to, onlyCAEvents, memberID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch host stats for club", slog.String("club_id", clubID), slog.String("member_id", memberID), slog.Any("err", err))
+		http.Error(w, "Failed to fetch host stats: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	host := newHost(database.HostStats{Member: *member}, clubID)
+	if len(hostStats) > 0 {
+		host = newHost(hostStats[0], clubID)
+	}
+
+	allEvents, err := h.DB.GetEvents(ctx, clubID, time.Time{}, time.Time{}, onlyCAEvents, memberID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch events for club", slog.String("club_id", clubID), slog.String("member_id", memberID), slog.Any("err", err))
+		http.Error(w, "Failed to fetch events: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	quarters := xtime.GetQuarters()
+	hostQuarters := calculateHostQuarters(quarters, allEvents)[memberID]
+	// only show the quarters since the first event of the host
+	for len(hostQuarters) > 0 && hostQuarters[len(hostQuarters)-1].Events == 0 {
+		hostQuarters = hostQuarters[:len(hostQuarters)-1]
+	}
+
+	events, err := h.DB.GetTopEventsByClub(ctx, clubID, from, to, onlyCAEvents, memberID, -1)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch top events for club", slog.String("club_id", clubID), slog.String("member_id", memberID), slog.Any("err", err))
+		http.Error(w, "Failed to fetch top events: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	eventClubAvatarURL := models.ImageURL(club.Club.AvatarURL, 32)
+	topEvents := make([]models.TopEvent, len(events))
+	for i, event := range events {
+		topEvents[i] = models.NewTopEvent(event, 32, eventClubAvatarURL)
+	}
+
+	if err = h.Templates().ExecuteTemplate(w, "tracker_club_host.gohtml", TrackerClubHostVars{
+		Club: models.NewClub(*club),
+		EventsFilter: EventsFilter{
+			FilterURL:    r.URL.Path,
+			From:         from,
+			To:           to,
+			OnlyCAEvents: onlyCAEvents,
+			Quarters:     quarters,
+		},
+		Host:           host,
+		HostQuarters:   hostQuarters,
+		Events:         topEvents,
+		EventsStatsURL: fmt.Sprintf("/tracker/club/%s/stats?event-creator=%s", clubID, memberID),
+	}); err != nil {
+		slog.ErrorContext(ctx, "Failed to render tracker club host template", slog.String("club_id", clubID), slog.String("member_id", memberID), slog.Any("err", err))
+	}
+}
+
+func newHost(host database.HostStats, clubID string) Host {
+	return Host{
+		Member:      models.NewMember(host.Member, clubID, 32),
+		HostURL:     fmt.Sprintf("/tracker/club/%s/hosts/%s", clubID, host.ID),
+		Events:      host.Events,
+		CAEvents:    host.CAEvents,
+		Accepted:    host.Accepted,
+		CheckIns:    host.CheckIns,
+		AvgAccepted: models.CalcAverage(host.Accepted, host.Events),
+		AvgCheckIns: models.CalcAverage(host.CheckIns, host.Events),
+		CheckInRate: models.CalcCheckInRate(host.Accepted, host.CheckIns),
+		CAShare:     models.CalcCheckInRate(host.Events, host.CAEvents),
+	}
+}
+
+func newHostQuarters(quarters []xtime.Quarter) []HostQuarter {
+	hostQuarters := make([]HostQuarter, len(quarters))
+	for i, quarter := range quarters {
+		hostQuarters[i].Quarter = quarter
+	}
+	return hostQuarters
+}
+
+// calculateHostQuarters sums up the events of each event creator per quarter.
+// The returned quarters of every event creator are in the same order as the given quarters.
+func calculateHostQuarters(quarters []xtime.Quarter, events []database.EventWithCheckIns) map[string][]HostQuarter {
+	type quarterRange struct {
+		from time.Time
+		to   time.Time
+	}
+	ranges := make([]quarterRange, len(quarters))
+	for i, quarter := range quarters {
+		from, to := xtime.GetRangeFromQuarter(quarter.Value)
+		ranges[i] = quarterRange{from: from, to: to}
+	}
+
+	hosts := make(map[string][]HostQuarter)
+	for _, event := range events {
+		for i, qr := range ranges {
+			if event.Time.Before(qr.from) || event.Time.After(qr.to) {
+				continue
+			}
+
+			hostQuarters, ok := hosts[event.CreatorID]
+			if !ok {
+				hostQuarters = newHostQuarters(quarters)
+				hosts[event.CreatorID] = hostQuarters
+			}
+			hostQuarters[i].Events++
+			hostQuarters[i].Accepted += event.Accepted
+			hostQuarters[i].CheckIns += event.CheckIns
+			break
+		}
+	}
+
+	for _, hostQuarters := range hosts {
+		for i := range hostQuarters {
+			hostQuarters[i].AvgAccepted = models.CalcAverage(hostQuarters[i].Accepted, hostQuarters[i].Events)
+			hostQuarters[i].AvgCheckIns = models.CalcAverage(hostQuarters[i].CheckIns, hostQuarters[i].Events)
+			hostQuarters[i].CheckInRate = models.CalcCheckInRate(hostQuarters[i].Accepted, hostQuarters[i].CheckIns)
+		}
+	}
+
+	return hosts
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -156,7 +157,7 @@ func (h *handler) TrackerClubDoImport(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err = h.DB.InsertClubImportJob(ctx, database.ClubImportJob{
+	clubImportJobID, err := h.DB.InsertClubImportJob(ctx, database.ClubImportJob{
 		ClubID:      club.ID,
 		CompletedAt: time.Time{},
 		LastTriedAt: time.Time{},
@@ -168,6 +169,10 @@ func (h *handler) TrackerClubDoImport(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err = h.DB.InsertJobs(ctx, []database.Job{h.NewJob(database.JobKindImportClub, strconv.Itoa(clubImportJobID))}); err != nil {
+		slog.ErrorContext(ctx, "Failed to queue club import job", slog.Int("job_id", clubImportJobID), slog.Any("err", err))
+	}
+
 	http.Redirect(w, r, "/tracker/club/import", http.StatusFound)
 }
 
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/topi314/campfire-tools/server/web/models"
 )
@@ -15,6 +16,7 @@ type TrackerClubMemberVars struct {
 	Club           models.Club
 	Events         []models.Event
 	AcceptedEvents []models.Event
+	Reliability    models.Reliability
 }
 
 func (h *handler) TrackerClubMember(w http.ResponseWriter, r *http.Request) {
@@ -72,6 +74,7 @@ func (h *handler) TrackerClubMember(w http.ResponseWriter, r *http.Request) {
 		Club:           clubModel,
 		Events:         trackerEvents,
 		AcceptedEvents: acceptedTrackerEvents,
+		Reliability:    models.NewReliability(events, acceptedEvents, time.Now()),
 	}); err != nil {
 		slog.ErrorContext(ctx, "Failed to render tracker club member template", slog.Any("err", err))
 	}
@@ -13,11 +13,14 @@ import (
 	"github.com/topi314/campfire-tools/server/web/models"
 )
 
+const noShowMembersLimit = 50
+
 type TrackerClubMembersVars struct {
 	models.Club
 	EventsFilter
 
-	Members []models.TopMember
+	Members       []models.TopMember
+	NoShowMembers []models.NoShowMember
 }
 
 func (h *handler) TrackerClubMembers(w http.ResponseWriter, r *http.Request) {
@@ -62,6 +65,18 @@ func (h *handler) TrackerClubMembers(w http.ResponseWriter, r *http.Request) {
 		trackerMembers[i] = models.NewTopMember(member, clubID, 32)
 	}
 
+	noShowMembers, err := h.DB.GetClubNoShowMembers(ctx, clubID, from, to, onlyCAEvents, eventCreator, noShowMembersLimit)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch no-show members for club", slog.String("club_id", clubID), slog.Any("err", err))
+		http.Error(w, "Failed to fetch no-show members: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	trackerNoShowMembers := make([]models.NoShowMember, len(noShowMembers))
+	for i, member := range noShowMembers {
+		trackerNoShowMembers[i] = models.NewNoShowMember(member, clubID, 32)
+	}
+
 	if err = h.Templates().ExecuteTemplate(w, "tracker_club_members.gohtml", TrackerClubMembersVars{
 		Club: models.NewClub(*club),
 		EventsFilter: EventsFilter{
@@ -73,7 +88,8 @@ func (h *handler) TrackerClubMembers(w http.ResponseWriter, r *http.Request) {
 			EventCreators:        eventCreators,
 			SelectedEventCreator: eventCreator,
 		},
-		Members: trackerMembers,
+		Members:       trackerMembers,
+		NoShowMembers: trackerNoShowMembers,
 	}); err != nil {
 		slog.ErrorContext(ctx, "Failed to render tracker club members template", slog.String("club_id", clubID), slog.Any("err", err))
 	}
@@ -14,6 +14,7 @@ type TrackerClubRaffleVars struct {
 	models.Club
 	EventsFilter
 	Events          []models.Event
+	Rewards         []models.Reward
 	SelectedEventID string
 	Error           string
 }
@@ -65,6 +66,13 @@ func (h *handler) renderTrackerClubRaffle(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	rewards, err := h.getRaffleRewards(r)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch rewards", slog.Any("err", err))
+		http.Error(w, "Failed to fetch rewards: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	if err = h.Templates().ExecuteTemplate(w, "tracker_club_raffle.gohtml", TrackerClubRaffleVars{
 		Club: clubModel,
 		EventsFilter: EventsFilter{
@@ -77,6 +85,7 @@ func (h *handler) renderTrackerClubRaffle(w http.ResponseWriter, r *http.Request
 			SelectedEventCreator: eventCreator,
 		},
 		Events:          trackerEvents,
+		Rewards:         rewards,
 		SelectedEventID: eventID,
 		Error:           errorMessage,
 	}); err != nil {
@@ -0,0 +1,153 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/topi314/campfire-tools/server/web/models"
+)
+
+const (
+	retentionMonths          = 6
+	retentionDefaultCohorts  = 12
+	retentionEventsLimit     = 50
+	lapsedRegularMinCheckIns = 3
+	lapsedRegularAfter       = 60 * 24 * time.Hour
+	lapsedRegularsLimit      = 50
+)
+
+type Retention struct {
+	Open             bool
+	Months           []int
+	Cohorts          []RetentionCohort
+	Events           []RetentionEvent
+	NewMembers       int
+	ReturningMembers int
+	LapsedRegulars   []LapsedRegular
+	LapsedDays       int
+	LapsedCheckIns   int
+}
+
+// RetentionCohort are the members who checked in at the club for the first time in the month.
+type RetentionCohort struct {
+	Month   time.Time
+	Members int
+	Months  []RetentionMonth
+}
+
+// RetentionMonth is how many members of a cohort checked in again a number of months after their first check-in.
+// Months which have not ended yet are incomplete.
+type RetentionMonth struct {
+	Members    int
+	Rate       float64
+	Incomplete bool
+	Future     bool
+}
+
+type RetentionEvent struct {
+	models.Event
+	NewMembers       int
+	ReturningMembers int
+	NewRate          float64
+}
+
+type LapsedRegular struct {
+	models.Member
+	CheckIns      int
+	LastCheckInAt time.Time
+}
+
+func (h *handler) calculateRetention(ctx context.Context, clubID string, clubAvatarURL string, from time.Time, to time.Time, onlyCAEvents bool, eventCreator string, retentionClosed bool) (*Retention, error) {
+	cohortRetention, err := h.DB.GetClubCohortRetention(ctx, clubID, from, to, retentionMonths)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch cohort retention: %w", err)
+	}
+
+	now := time.Now().UTC()
+	currentMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	var cohorts []RetentionCohort
+	for _, row := range cohortRetention {
+		if len(cohorts) == 0 || !cohorts[len(cohorts)-1].Month.Equal(row.Cohort) {
+			cohort := RetentionCohort{
+				Month:  row.Cohort,
+				Months: make([]RetentionMonth, retentionMonths),
+			}
+			for i := range cohort.Months {
+				month := row.Cohort.AddDate(0, i+1, 0)
+				cohort.Months[i].Future = month.After(currentMonth)
+				cohort.Months[i].Incomplete = month.Equal(currentMonth)
+			}
+			cohorts = append(cohorts, cohort)
+		}
+
+		cohort := &cohorts[len(cohorts)-1]
+		if row.Month == 0 {
+			cohort.Members = row.Members
+			continue
+		}
+		cohort.Months[row.Month-1].Members = row.Members
+	}
+	for i := range cohorts {
+		for j := range cohorts[i].Months {
+			cohorts[i].Months[j].Rate = models.CalcCheckInRate(cohorts[i].Members, cohorts[i].Months[j].Members)
+		}
+	}
+	// without a date filter only the most recent cohorts are shown
+	if from.IsZero() && to.IsZero() && len(cohorts) > retentionDefaultCohorts {
+		cohorts = cohorts[len(cohorts)-retentionDefaultCohorts:]
+	}
+
+	eventRetention, err := h.DB.GetClubEventRetention(ctx, clubID, from, to, onlyCAEvents, eventCreator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch event retention: %w", err)
+	}
+
+	var newMembers, returningMembers int
+	events := make([]RetentionEvent, len(eventRetention))
+	for i, event := range eventRetention {
+		events[i] = RetentionEvent{
+			Event:            models.NewEvent(event.Event, 32, clubAvatarURL),
+			NewMembers:       event.NewMembers,
+			ReturningMembers: event.ReturningMembers,
+			NewRate:          models.CalcCheckInRate(event.NewMembers+event.ReturningMembers, event.NewMembers),
+		}
+		newMembers += event.NewMembers
+		returningMembers += event.ReturningMembers
+	}
+	if len(events) > retentionEventsLimit {
+		events = events[:retentionEventsLimit]
+	}
+
+	lapsedMembers, err := h.DB.GetClubLapsedMembers(ctx, clubID, lapsedRegularMinCheckIns, now.Add(-lapsedRegularAfter), lapsedRegularsLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch lapsed members: %w", err)
+	}
+
+	lapsedRegulars := make([]LapsedRegular, len(lapsedMembers))
+	for i, member := range lapsedMembers {
+		lapsedRegulars[i] = LapsedRegular{
+			Member:        models.NewMember(member.Member, clubID, 32),
+			CheckIns:      member.CheckIns,
+			LastCheckInAt: member.LastCheckInAt,
+		}
+	}
+
+	months := make([]int, retentionMonths)
+	for i := range months {
+		months[i] = i + 1
+	}
+
+	return &Retention{
+		Open:             !retentionClosed,
+		Months:           months,
+		Cohorts:          cohorts,
+		Events:           events,
+		NewMembers:       newMembers,
+		ReturningMembers: returningMembers,
+		LapsedRegulars:   lapsedRegulars,
+		LapsedDays:       int(lapsedRegularAfter.Hours() / 24),
+		LapsedCheckIns:   lapsedRegularMinCheckIns,
+	}, nil
+}
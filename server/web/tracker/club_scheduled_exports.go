@@ -0,0 +1,262 @@
+package tracker
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo/webhook"
+
+	"github.com/topi314/campfire-tools/server"
+	"github.com/topi314/campfire-tools/server/auth"
+	"github.com/topi314/campfire-tools/server/database"
+	"github.com/topi314/campfire-tools/server/web/models"
+)
+
+const scheduledExportRunsLimit = 50
+
+type TrackerClubScheduledExportsVars struct {
+	models.Club
+	ScheduledExports []models.ScheduledExport
+	Runs             []models.ScheduledExportRun
+	Presets          []models.ExportPreset
+	S3Enabled        bool
+	S3Bucket         string
+	Error            string
+}
+
+func (h *handler) TrackerClubScheduledExports(w http.ResponseWriter, r *http.Request) {
+	h.renderTrackerClubScheduledExports(w, r, "")
+}
+
+func (h *handler) renderTrackerClubScheduledExports(w http.ResponseWriter, r *http.Request, errorMessage string) {
+	ctx := r.Context()
+	session := auth.GetSession(r)
+
+	clubID := r.PathValue("club_id")
+
+	club, err := h.DB.GetClub(ctx, clubID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			h.NotFound(w, r)
+			return
+		}
+		slog.ErrorContext(ctx, "Failed to get club", slog.String("club_id", clubID), slog.Any("err", err))
+		http.Error(w, "Failed to get club: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	scheduledExports, err := h.DB.GetClubScheduledExports(ctx, clubID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get club scheduled exports", slog.String("club_id", clubID), slog.Any("err", err))
+		http.Error(w, "Failed to get club scheduled exports: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	runs, err := h.DB.GetClubScheduledExportRuns(ctx, clubID, scheduledExportRunsLimit)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get club scheduled export runs", slog.String("club_id", clubID), slog.Any("err", err))
+		http.Error(w, "Failed to get club scheduled export runs: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	presets, err := h.DB.GetExportPresets(ctx, session.UserID, clubID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get export presets", slog.String("club_id", clubID), slog.Any("err", err))
+		http.Error(w, "Failed to get export presets: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	trackerScheduledExports := make([]models.ScheduledExport, len(scheduledExports))
+	for i, scheduledExport := range scheduledExports {
+		trackerScheduledExports[i] = models.NewScheduledExport(scheduledExport, exportEventRangeName(scheduledExport.EventRange), h.scheduledExportDestinationName(scheduledExport.ScheduledExport))
+	}
+
+	trackerRuns := make([]models.ScheduledExportRun, len(runs))
+	for i, run := range runs {
+		trackerRuns[i] = models.NewScheduledExportRun(run)
+	}
+
+	// only presets with an event range know which events to export on their own
+	var trackerPresets []models.ExportPreset
+	for _, preset := range presets {
+		if preset.EventRange != "" {
+			trackerPresets = append(trackerPresets, models.NewExportPreset(preset, exportEventRangeName(preset.EventRange)))
+		}
+	}
+
+	var s3Bucket string
+	if h.S3 != nil {
+		s3Bucket = h.S3.Bucket()
+	}
+
+	if err = h.Templates().ExecuteTemplate(w, "tracker_club_scheduled_exports.gohtml", TrackerClubScheduledExportsVars{
+		Club:             models.NewClub(*club),
+		ScheduledExports: trackerScheduledExports,
+		Runs:             trackerRuns,
+		Presets:          trackerPresets,
+		S3Enabled:        h.S3 != nil,
+		S3Bucket:         s3Bucket,
+		Error:            errorMessage,
+	}); err != nil {
+		slog.ErrorContext(ctx, "Failed to render tracker club scheduled exports template", slog.String("club_id", clubID), slog.Any("err", err))
+	}
+}
+
+// scheduledExportDestinationName describes where the exports are delivered to, without revealing webhook URLs.
+func (h *handler) scheduledExportDestinationName(scheduledExport database.ScheduledExport) string {
+	switch scheduledExport.Destination {
+	case database.ScheduledExportDestinationDiscord:
+		if scheduledExport.Target == "" {
+			return "Discord notification webhook"
+		}
+		return "Discord webhook"
+	case database.ScheduledExportDestinationS3:
+		bucket := "<not configured>"
+		if h.S3 != nil {
+			bucket = h.S3.Bucket()
+		}
+		return fmt.Sprintf("s3://%s/%s/", bucket, scheduledExport.Target)
+	}
+	return string(scheduledExport.Destination)
+}
+
+func (h *handler) PostTrackerClubScheduledExport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	session := auth.GetSession(r)
+
+	if err := r.ParseForm(); err != nil {
+		h.renderTrackerClubScheduledExports(w, r, "Failed to parse form data: "+err.Error())
+		return
+	}
+
+	clubID := r.PathValue("club_id")
+	schedule := strings.TrimSpace(r.Form.Get("schedule"))
+	destination := database.ScheduledExportDestination(r.Form.Get("destination"))
+	target := strings.TrimSpace(r.Form.Get("target"))
+
+	presetID, err := strconv.Atoi(r.Form.Get("preset"))
+	if err != nil {
+		h.renderTrackerClubScheduledExports(w, r, "Select a preset")
+		return
+	}
+	preset, err := h.DB.GetExportPreset(ctx, presetID, session.UserID, clubID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			h.renderTrackerClubScheduledExports(w, r, "Preset not found")
+			return
+		}
+		slog.ErrorContext(ctx, "Failed to get export preset", slog.Int("preset_id", presetID), slog.Any("err", err))
+		h.renderTrackerClubScheduledExports(w, r, "Failed to get export preset")
+		return
+	}
+	if preset.EventRange == "" {
+		h.renderTrackerClubScheduledExports(w, r, "Only presets with an event range can be scheduled")
+		return
+	}
+
+	nextRunAt, err := server.NextScheduledExportRun(schedule, time.Now())
+	if err != nil {
+		h.renderTrackerClubScheduledExports(w, r, err.Error())
+		return
+	}
+
+	switch destination {
+	case database.ScheduledExportDestinationDiscord:
+		if target == "" {
+			h.renderTrackerClubScheduledExports(w, r, "Enter a Discord webhook URL")
+			return
+		}
+		if _, err = webhook.NewWithURL(target); err != nil {
+			h.renderTrackerClubScheduledExports(w, r, "Invalid Discord webhook URL: "+err.Error())
+			return
+		}
+	case database.ScheduledExportDestinationS3:
+		if h.S3 == nil {
+			h.renderTrackerClubScheduledExports(w, r, "S3 storage is not configured")
+			return
+		}
+		// the key prefix is always below the folder of the club, so clubs can not write into each other's exports
+		target = path.Join(clubID, strings.Trim(path.Clean("/"+target), "/"))
+	default:
+		h.renderTrackerClubScheduledExports(w, r, "Invalid destination: "+string(destination))
+		return
+	}
+
+	var createdBy *string
+	if session.UserID != "" {
+		createdBy = &session.UserID
+	}
+
+	if _, err = h.DB.InsertScheduledExport(ctx, database.ScheduledExport{
+		ClubID:      clubID,
+		PresetID:    preset.ID,
+		Schedule:    schedule,
+		Destination: destination,
+		Target:      target,
+		NextRunAt:   nextRunAt,
+		CreatedBy:   createdBy,
+	}); err != nil {
+		slog.ErrorContext(ctx, "Failed to insert scheduled export", slog.String("club_id", clubID), slog.Any("err", err))
+		h.renderTrackerClubScheduledExports(w, r, "Failed to create scheduled export")
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/tracker/club/%s/scheduled-exports", clubID), http.StatusSeeOther)
+}
+
+// TrackerClubScheduledExportRun queues a run of the scheduled export right away.
+func (h *handler) TrackerClubScheduledExportRun(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	clubID := r.PathValue("club_id")
+	id, err := strconv.Atoi(r.PathValue("scheduled_export_id"))
+	if err != nil {
+		h.NotFound(w, r)
+		return
+	}
+
+	scheduledExport, err := h.DB.GetScheduledExport(ctx, id, clubID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			h.NotFound(w, r)
+			return
+		}
+		slog.ErrorContext(ctx, "Failed to get scheduled export", slog.Int("scheduled_export_id", id), slog.Any("err", err))
+		http.Error(w, "Failed to get scheduled export: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err = h.RunScheduledExport(ctx, *scheduledExport); err != nil {
+		slog.ErrorContext(ctx, "Failed to run scheduled export", slog.Int("scheduled_export_id", id), slog.Any("err", err))
+		http.Error(w, "Failed to run scheduled export", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/tracker/club/%s/scheduled-exports", clubID), http.StatusSeeOther)
+}
+
+func (h *handler) TrackerClubScheduledExportDelete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	clubID := r.PathValue("club_id")
+	id, err := strconv.Atoi(r.PathValue("scheduled_export_id"))
+	if err != nil {
+		h.NotFound(w, r)
+		return
+	}
+
+	if err = h.DB.DeleteScheduledExport(ctx, id, clubID); err != nil {
+		slog.ErrorContext(ctx, "Failed to delete scheduled export", slog.String("club_id", clubID), slog.Any("err", err))
+		http.Error(w, "Failed to delete scheduled export", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/tracker/club/%s/scheduled-exports", clubID), http.StatusSeeOther)
+}
@@ -14,17 +14,10 @@ import (
 
 	"github.com/topi314/campfire-tools/internal/xquery"
 	"github.com/topi314/campfire-tools/internal/xtime"
+	"github.com/topi314/campfire-tools/server/database"
 	"github.com/topi314/campfire-tools/server/web/models"
 )
 
-const (
-	OriginLeagueGoal    = 1
-	GreatLeagueGoal     = 61
-	UltraLeagueGoal     = 250
-	MasterLeagueGoal    = 750
-	LegendaryLeagueGoal = 1500
-)
-
 const (
 	digitalCodeRate        = 0.25
 	digitalCodeSpecialRate = 0.35
@@ -43,6 +36,8 @@ type TrackerClubStatsVars struct {
 	EventCategories models.EventCategories
 	LeagueGoals     LeagueGoals
 	DigitalCodes    DigitalCodes
+	Retention       Retention
+	Attendance      Attendance
 }
 
 type LeagueGoals struct {
@@ -95,8 +90,17 @@ func (h *handler) TrackerClubStats(w http.ResponseWriter, r *http.Request) {
 	categoriesClosed := xquery.ParseBool(query, "event-categories-closed", false)
 	digitalCodesClosed := xquery.ParseBool(query, "digital-codes-closed", false)
 	leagueGoalsClosed := xquery.ParseBool(query, "league-goals-closed", false)
+	retentionClosed := xquery.ParseBool(query, "retention-closed", false)
+	attendanceClosed := xquery.ParseBool(query, "attendance-closed", false)
+	timeZone := xquery.ParseString(query, "timezone", "UTC")
 	leagueGoalQuarter := query.Get("league-goal-quarter")
 
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		http.Error(w, "Invalid timezone: "+timeZone, http.StatusBadRequest)
+		return
+	}
+
 	club, err := h.DB.GetClub(ctx, clubID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -134,6 +138,18 @@ func (h *handler) TrackerClubStats(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	retention, err := h.calculateRetention(ctx, clubID, models.ImageURL(club.Club.AvatarURL, 32), from, to, onlyCAEvents, eventCreator, retentionClosed)
+	if err != nil {
+		http.Error(w, "Failed to fetch retention: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	attendance, err := h.calculateAttendance(ctx, clubID, from, to, onlyCAEvents, eventCreator, loc, attendanceClosed)
+	if err != nil {
+		http.Error(w, "Failed to fetch attendance: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	if err = h.Templates().ExecuteTemplate(w, "tracker_club_stats.gohtml", TrackerClubStatsVars{
 		Club: models.NewClub(*club),
 		EventsFilter: EventsFilter{
@@ -148,6 +164,8 @@ func (h *handler) TrackerClubStats(w http.ResponseWriter, r *http.Request) {
 		EventCategories: *eventCategories,
 		DigitalCodes:    *digitalCodes,
 		LeagueGoals:     *goals,
+		Retention:       *retention,
+		Attendance:      *attendance,
 	}); err != nil {
 		slog.ErrorContext(ctx, "Failed to render tracker club stats template", slog.String("club_id", clubID), slog.Any("err", err))
 	}
@@ -163,9 +181,14 @@ func (h *handler) calculateEventCategories(ctx context.Context, clubID string, f
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch event check-in and accepted counts: %w", err)
 	}
+	allCategories, err := h.getEventCategories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch event categories: %w", err)
+	}
+
 	eventCategories := make(map[string]models.EventCategory)
 	for _, event := range events {
-		category := h.getEventCategory(event.CampfireLiveEventName)
+		category := h.getEventCategory(allCategories, event.CampfireLiveEventName)
 
 		eventCategory, ok := eventCategories[category]
 		if !ok {
@@ -212,6 +235,11 @@ func (h *handler) calculateDigitalCodes(ctx context.Context, clubID string, digi
 	startDate := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).AddDate(0, 1, 0)
 	endDate := time.Date(2025, 10, 1, 0, 0, 0, 0, now.Location())
 
+	eventConfig, err := h.EventConfig.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch event config: %w", err)
+	}
+
 	var digitalCodeMonths []DigitalCodeMonth
 	for date := startDate; !date.Before(endDate); date = date.AddDate(0, -1, 0) {
 		months := 3
@@ -220,7 +248,7 @@ func (h *handler) calculateDigitalCodes(ctx context.Context, clubID string, digi
 		}
 		from := date.AddDate(0, -months, 0)
 		to := date.Add(-time.Second)
-		_, checkIns, err := h.DB.GetClubTotalCheckInsAcceptedExcludingLiveEventPatterns(ctx, clubID, from, to, true, "", digitalCodeExcludePatterns())
+		_, checkIns, err := h.DB.GetClubTotalCheckInsAcceptedExcludingLiveEventPatterns(ctx, clubID, from, to, true, "", eventConfig.DigitalCodeExcludePatterns)
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch total check-ins and accepted members for digital codes: %w", err)
 		}
@@ -275,37 +303,14 @@ func (h *handler) calculateLeagueGoals(ctx context.Context, clubID string, from
 
 	totalCAProjectedCheckIns, quarterDays, quarterDaysRemaining := models.CalcCAProjectedCheckIns(from, to, totalCACheckIns)
 
-	leagueGoals := []LeagueGoal{
-		{
-			Name:       "Origin League",
-			Goal:       OriginLeagueGoal,
-			Progress:   models.CalcCheckInProgress(OriginLeagueGoal, totalCACheckIns),
-			Projection: OriginLeagueGoal <= totalCAProjectedCheckIns,
-		},
-		{
-			Name:       "Great League",
-			Goal:       GreatLeagueGoal,
-			Progress:   models.CalcCheckInProgress(GreatLeagueGoal, totalCACheckIns),
-			Projection: GreatLeagueGoal <= totalCAProjectedCheckIns,
-		},
-		{
-			Name:       "Ultra League",
-			Goal:       UltraLeagueGoal,
-			Progress:   models.CalcCheckInProgress(UltraLeagueGoal, totalCACheckIns),
-			Projection: UltraLeagueGoal <= totalCAProjectedCheckIns,
-		},
-		{
-			Name:       "Master League",
-			Goal:       MasterLeagueGoal,
-			Progress:   models.CalcCheckInProgress(MasterLeagueGoal, totalCACheckIns),
-			Projection: MasterLeagueGoal <= totalCAProjectedCheckIns,
-		},
-		{
-			Name:       "Legendary League",
-			Goal:       LegendaryLeagueGoal,
-			Progress:   models.CalcCheckInProgress(LegendaryLeagueGoal, totalCACheckIns),
-			Projection: LegendaryLeagueGoal <= totalCAProjectedCheckIns,
-		},
+	leagueGoals := make([]LeagueGoal, 0, len(models.Leagues))
+	for _, league := range models.Leagues {
+		leagueGoals = append(leagueGoals, LeagueGoal{
+			Name:       league.Name,
+			Goal:       league.Goal,
+			Progress:   models.CalcCheckInProgress(league.Goal, totalCACheckIns),
+			Projection: league.Goal <= totalCAProjectedCheckIns,
+		})
 	}
 
 	return &LeagueGoals{
@@ -327,67 +332,60 @@ const (
 	EventCategoryNoEvent = "No Event"
 )
 
-var AllEventCategories = map[string][]string{
-	"Raid Day":          {"Raid Day", "Mega Raid"},
-	"Raid Hour":         {"Raid Hour"},
-	"Max Monday":        {"Max Monday"},
-	"Research Day":      {"Research Day"},
-	"Hatch Day":         {"Hatch Day"},
-	"Community Day":     {"Community Day", "Community Classic Day"},
-	"Spotlight Hour":    {"Spotlight Hour"},
-	"Max Battle":        {"Max Battle Weekend", "Max Battle Day", "Max Weekend", "Gigantamax", "GMAX"},
-	"GO Tour":           {"GO Tour"},
-	"GO Fest":           {"GO Fest"},
-	"GO Wild Area":      {"GOWA"},
-	"Friendship Friday": {"Friendship Friday"},
-}
-
-var orderedEventCategories = []string{
-	"GO Wild Area",
-	"GO Fest",
-	"GO Tour",
-	"Community Day",
-	"Max Battle",
-	"Research Day",
-	"Hatch Day",
-	"Friendship Friday",
-	"Raid Day",
-	"Raid Hour",
-	"Max Monday",
-	"Spotlight Hour",
-}
-
-var DigitalCodeExcludedCategories = []string{
-	"Friendship Friday",
-}
+// eventCategories are the event categories configured on the admin page, ordered by their position.
+type eventCategories []database.EventCategory
 
-func digitalCodeExcludePatterns() []string {
-	var patterns []string
-	for _, category := range DigitalCodeExcludedCategories {
-		for _, name := range AllEventCategories[category] {
-			patterns = append(patterns, "%"+name+"%")
-		}
+func (h *handler) getEventCategories(ctx context.Context) (eventCategories, error) {
+	cfg, err := h.EventConfig.Get(ctx)
+	if err != nil {
+		return nil, err
 	}
-	return patterns
+	return cfg.Categories, nil
 }
 
-func eventCategoryFromName(eventName string) string {
+// fromName returns the first category with a pattern contained in the live event name, ignoring case.
+func (c eventCategories) fromName(eventName string) string {
 	eventName = strings.ToLower(strings.TrimSpace(eventName))
 	if eventName == "" {
 		return EventCategoryNoEvent
 	}
-	for _, category := range orderedEventCategories {
-		for _, pattern := range AllEventCategories[category] {
+	for _, category := range c {
+		for _, pattern := range category.Patterns {
 			if strings.Contains(eventName, strings.ToLower(pattern)) {
-				return category
+				return category.Name
 			}
 		}
 	}
 	return EventCategoryOther
 }
 
-func (h *handler) getEventCategory(eventName string) string {
-	category := eventCategoryFromName(eventName)
+// filterNames returns all event categories events can be filtered by.
+func (c eventCategories) filterNames() []string {
+	names := make([]string, 0, len(c)+2)
+	for _, category := range c {
+		names = append(names, category.Name)
+	}
+	return append(names, EventCategoryOther, EventCategoryNoEvent)
+}
+
+// parse matches the given category names case-insensitively against filterNames.
+func (c eventCategories) parse(names []string) ([]string, error) {
+	allCategories := c.filterNames()
+	var categories []string
+	for _, name := range names {
+		i := slices.IndexFunc(allCategories, func(category string) bool {
+			return strings.EqualFold(category, name)
+		})
+		if i == -1 {
+			return nil, fmt.Errorf("invalid category: %s", name)
+		}
+		categories = append(categories, allCategories[i])
+	}
+	return categories, nil
+}
+
+func (h *handler) getEventCategory(categories eventCategories, eventName string) string {
+	category := categories.fromName(eventName)
 	if category == EventCategoryOther && h.Cfg.WarnUnknownEventCategories {
 		slog.Warn("Unknown event category", slog.String("event_name", eventName))
 	}
@@ -6,6 +6,11 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo/webhook"
 
 	"github.com/topi314/campfire-tools/internal/xquery"
 	"github.com/topi314/campfire-tools/server/auth"
@@ -30,8 +35,48 @@ func (h *handler) TrackerClubUpdate(w http.ResponseWriter, r *http.Request) {
 	pinned := xquery.ParseBool(r.Form, "pinned", false)
 	autoEventImport := xquery.ParseBool(r.Form, "auto_event_import", false)
 	verificationChannelID := r.Form.Get("verification_channel_id")
+	raffleCooldownDays := xquery.ParseInt(r.Form, "raffle_cooldown_days", 0)
+	raffleExcludeCommunityAmbassadors := xquery.ParseBool(r.Form, "raffle_exclude_community_ambassadors", false)
+	digestWebhookURL := strings.TrimSpace(r.Form.Get("digest_webhook_url"))
+	digestWebhookRemove := xquery.ParseBool(r.Form, "digest_webhook_remove", false)
+	digestWeekly := xquery.ParseBool(r.Form, "digest_weekly", false)
+	digestQuarterly := xquery.ParseBool(r.Form, "digest_quarterly", false)
+	digestWeekday := xquery.ParseInt(r.Form, "digest_weekday", int(time.Monday))
+	digestHour := xquery.ParseInt(r.Form, "digest_hour", 9)
+	leaderboardOptOut := xquery.ParseBool(r.Form, "leaderboard_opt_out", false)
+
+	raffleBlockedMembers := []string{}
+	for _, memberID := range strings.Split(r.Form.Get("raffle_blocked_members"), "\n") {
+		memberID = strings.TrimSpace(memberID)
+		if memberID == "" || slices.Contains(raffleBlockedMembers, memberID) {
+			continue
+		}
+		raffleBlockedMembers = append(raffleBlockedMembers, memberID)
+	}
+
+	if raffleCooldownDays < 0 {
+		http.Error(w, "Raffle cooldown days must not be negative", http.StatusBadRequest)
+		return
+	}
+
+	if digestWeekday < int(time.Sunday) || digestWeekday > int(time.Saturday) {
+		http.Error(w, "Digest weekday must be between 0 and 6", http.StatusBadRequest)
+		return
+	}
+
+	if digestHour < 0 || digestHour > 23 {
+		http.Error(w, "Digest hour must be between 0 and 23", http.StatusBadRequest)
+		return
+	}
+
+	if digestWebhookURL != "" {
+		if _, err := webhook.NewWithURL(digestWebhookURL); err != nil {
+			http.Error(w, "Invalid digest webhook URL: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
 
-	_, err := h.DB.GetClub(ctx, clubID)
+	club, err := h.DB.GetClub(ctx, clubID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			h.NotFound(w, r)
@@ -64,5 +109,28 @@ func (h *handler) TrackerClubUpdate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err = h.DB.UpdateClubRaffleExclusions(ctx, clubID, raffleBlockedMembers, raffleCooldownDays, raffleExcludeCommunityAmbassadors); err != nil {
+		http.Error(w, "Failed to update club raffle exclusions: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err = h.DB.UpdateClubLeaderboardOptOut(ctx, clubID, leaderboardOptOut); err != nil {
+		http.Error(w, "Failed to update club leaderboard opt out: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// the webhook url is never rendered, so an empty input keeps the current one
+	webhookURL := club.DigestWebhookURL
+	if digestWebhookURL != "" {
+		webhookURL = &digestWebhookURL
+	} else if digestWebhookRemove {
+		webhookURL = nil
+	}
+
+	if err = h.DB.UpdateClubDigest(ctx, clubID, webhookURL, digestWeekly, digestQuarterly, time.Weekday(digestWeekday), digestHour); err != nil {
+		http.Error(w, "Failed to update club digest: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	http.Redirect(w, r, fmt.Sprintf("/tracker/club/%s", clubID), http.StatusSeeOther)
 }
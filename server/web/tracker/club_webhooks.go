@@ -0,0 +1,156 @@
+package tracker
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/topi314/campfire-tools/server"
+	"github.com/topi314/campfire-tools/server/auth"
+	"github.com/topi314/campfire-tools/server/database"
+	"github.com/topi314/campfire-tools/server/web/models"
+)
+
+const webhookDeliveriesLimit = 50
+
+type TrackerClubWebhooksVars struct {
+	models.Club
+	Webhooks   []models.Webhook
+	Deliveries []models.WebhookDelivery
+	Topics     []database.WebhookTopic
+	NewSecret  string
+	Error      string
+}
+
+func (h *handler) TrackerClubWebhooks(w http.ResponseWriter, r *http.Request) {
+	h.renderTrackerClubWebhooks(w, r, "", "")
+}
+
+func (h *handler) renderTrackerClubWebhooks(w http.ResponseWriter, r *http.Request, newSecret string, errorMessage string) {
+	ctx := r.Context()
+
+	clubID := r.PathValue("club_id")
+
+	club, err := h.DB.GetClub(ctx, clubID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			h.NotFound(w, r)
+			return
+		}
+		slog.ErrorContext(ctx, "Failed to get club", slog.String("club_id", clubID), slog.Any("err", err))
+		http.Error(w, "Failed to get club: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	webhooks, err := h.DB.GetClubWebhooks(ctx, clubID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get club webhooks", slog.String("club_id", clubID), slog.Any("err", err))
+		http.Error(w, "Failed to get club webhooks: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	deliveries, err := h.DB.GetClubWebhookDeliveries(ctx, clubID, webhookDeliveriesLimit)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get club webhook deliveries", slog.String("club_id", clubID), slog.Any("err", err))
+		http.Error(w, "Failed to get club webhook deliveries: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	trackerWebhooks := make([]models.Webhook, len(webhooks))
+	for i, webhook := range webhooks {
+		trackerWebhooks[i] = models.NewWebhook(webhook)
+	}
+
+	trackerDeliveries := make([]models.WebhookDelivery, len(deliveries))
+	for i, delivery := range deliveries {
+		trackerDeliveries[i] = models.NewWebhookDelivery(delivery)
+	}
+
+	if err = h.Templates().ExecuteTemplate(w, "tracker_club_webhooks.gohtml", TrackerClubWebhooksVars{
+		Club:       models.NewClub(*club),
+		Webhooks:   trackerWebhooks,
+		Deliveries: trackerDeliveries,
+		Topics:     database.AllWebhookTopics,
+		NewSecret:  newSecret,
+		Error:      errorMessage,
+	}); err != nil {
+		slog.ErrorContext(ctx, "Failed to render tracker club webhooks template", slog.String("club_id", clubID), slog.Any("err", err))
+	}
+}
+
+func (h *handler) PostTrackerClubWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	session := auth.GetSession(r)
+
+	if err := r.ParseForm(); err != nil {
+		h.renderTrackerClubWebhooks(w, r, "", "Failed to parse form data: "+err.Error())
+		return
+	}
+
+	clubID := r.PathValue("club_id")
+	targetURL := strings.TrimSpace(r.Form.Get("url"))
+
+	topics := []string{}
+	for _, topic := range r.Form["topics"] {
+		if !slices.Contains(database.AllWebhookTopics, database.WebhookTopic(topic)) {
+			h.renderTrackerClubWebhooks(w, r, "", "Invalid topic: "+topic)
+			return
+		}
+		if !slices.Contains(topics, topic) {
+			topics = append(topics, topic)
+		}
+	}
+
+	if err := server.ValidateWebhookURL(ctx, targetURL); err != nil {
+		h.renderTrackerClubWebhooks(w, r, "", "Invalid URL: "+err.Error())
+		return
+	}
+	if len(topics) == 0 {
+		h.renderTrackerClubWebhooks(w, r, "", "Select at least one topic")
+		return
+	}
+
+	var createdBy *string
+	if session.UserID != "" {
+		createdBy = &session.UserID
+	}
+
+	secret := server.NewWebhookSecret()
+	if _, err := h.DB.InsertWebhook(ctx, database.Webhook{
+		ClubID:    clubID,
+		URL:       targetURL,
+		Secret:    secret,
+		Topics:    topics,
+		CreatedBy: createdBy,
+	}); err != nil {
+		slog.ErrorContext(ctx, "Failed to insert webhook", slog.String("club_id", clubID), slog.Any("err", err))
+		h.renderTrackerClubWebhooks(w, r, "", "Failed to create webhook")
+		return
+	}
+
+	h.renderTrackerClubWebhooks(w, r, secret, "")
+}
+
+func (h *handler) TrackerClubWebhookDelete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	clubID := r.PathValue("club_id")
+	id, err := strconv.Atoi(r.PathValue("webhook_id"))
+	if err != nil {
+		h.NotFound(w, r)
+		return
+	}
+
+	if err = h.DB.DeleteWebhook(ctx, id, clubID); err != nil {
+		slog.ErrorContext(ctx, "Failed to delete webhook", slog.String("club_id", clubID), slog.Any("err", err))
+		http.Error(w, "Failed to delete webhook", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/tracker/club/%s/webhooks", clubID), http.StatusSeeOther)
+}
@@ -83,6 +83,15 @@ func (h *handler) TrackerEventDoImport(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *handler) importAllEvents(ctx context.Context, eventIDs []string) error {
+	events, err := h.fetchImportEvents(ctx, eventIDs)
+	if err != nil {
+		return err
+	}
+
+	return h.bulkProcessEvents(ctx, events)
+}
+
+func (h *handler) fetchImportEvents(ctx context.Context, eventIDs []string) ([]campfire.Event, error) {
 	var (
 		events []campfire.Event
 		mu     sync.Mutex
@@ -108,14 +117,14 @@ func (h *handler) importAllEvents(ctx context.Context, eventIDs []string) error
 	}
 
 	if err := eg.Wait(); err != nil {
-		return fmt.Errorf("failed to fetch events: %w", err)
+		return nil, fmt.Errorf("failed to fetch events: %w", err)
 	}
 
 	if len(events) == 0 {
-		return errors.New("no valid events to import")
+		return nil, errors.New("no valid events to import")
 	}
 
 	slog.InfoContext(ctx, "Fetched all events", slog.Int("count", len(events)))
 
-	return h.bulkProcessEvents(ctx, events)
+	return events, nil
 }
@@ -30,7 +30,14 @@ func (h *handler) TrackerEventStats(w http.ResponseWriter, r *http.Request) {
 		clubIDs = append(clubIDs, id)
 	}
 
-	event, eventOK := findConfiguredEvent(eventKey)
+	configuredEvents, err := h.getConfiguredEvents(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch configured events", slog.Any("err", err))
+		http.Error(w, "Failed to fetch configured events: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	event, eventOK := findConfiguredEvent(configuredEvents, eventKey)
 	var liveEventIDs []string
 	if eventOK {
 		liveEventIDs = event.LiveEventIDs()
@@ -56,7 +63,7 @@ func (h *handler) TrackerEventStats(w http.ResponseWriter, r *http.Request) {
 			return err
 		})
 	}
-	if err := eg.Wait(); err != nil {
+	if err = eg.Wait(); err != nil {
 		http.Error(w, "Failed to fetch event stats: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -83,7 +90,7 @@ func (h *handler) TrackerEventStats(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	for _, e := range ConfiguredEvents {
+	for _, e := range configuredEvents {
 		vars.Events = append(vars.Events, models.EventOption{
 			Key:      e.Key,
 			Name:     e.Name,
@@ -103,7 +110,7 @@ func (h *handler) TrackerEventStats(w http.ResponseWriter, r *http.Request) {
 		buildEventStats(&vars, event, selectedClubs, rsvps)
 	}
 
-	if err := h.Templates().ExecuteTemplate(w, "tracker_event_stats.gohtml", vars); err != nil {
+	if err = h.Templates().ExecuteTemplate(w, "tracker_event_stats.gohtml", vars); err != nil {
 		slog.ErrorContext(ctx, "Failed to render event stats template", slog.Any("err", err))
 	}
 }
@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -15,6 +17,7 @@ import (
 
 	"golang.org/x/sync/errgroup"
 
+	"github.com/topi314/campfire-tools/internal/xlsx"
 	"github.com/topi314/campfire-tools/internal/xquery"
 	"github.com/topi314/campfire-tools/server/campfire"
 )
@@ -47,7 +50,85 @@ var defaultFields = []string{
 	FieldEventName,
 }
 
+var fieldNames = []struct {
+	Field string
+	Name  string
+}{
+	{FieldUserID, "User ID"},
+	{FieldUsername, "Username"},
+	{FieldDisplayName, "Display Name"},
+	{FieldRSVPStatus, "RSVP Status"},
+	{FieldEventID, "Event ID"},
+	{FieldEventName, "Event Name"},
+	{FieldEventURL, "Event URL"},
+	{FieldEventTime, "Event Time"},
+	{FieldEventClubID, "Event Club ID"},
+	{FieldEventCreatorUserID, "Event Creator ID"},
+	{FieldEventCreatorUsername, "Event Creator Username"},
+	{FieldEventCreatorDisplayName, "Event Creator Display Name"},
+	{FieldEventDiscordInterested, "Event Discord Interested"},
+	{FieldEventCreatedByCommunityAmbassador, "Event Created by Community Ambassador"},
+	{FieldEventCampfireLiveEventID, "Event Campfire Live Event ID"},
+	{FieldEventCampfireLiveEventName, "Event Campfire Live Event Name"},
+}
+
+// ExportOptions are the options of the export form, independent of which events are exported.
+type ExportOptions struct {
+	IncludedFields        []string
+	Format                string
+	CombineCSVs           bool
+	IncludeMissingMembers bool
+}
+
+// ExportField is an option of the included fields select.
+type ExportField struct {
+	Field    string
+	Name     string
+	Selected bool
+}
+
+func defaultExportOptions() ExportOptions {
+	return ExportOptions{
+		IncludedFields:        defaultFields,
+		Format:                exportFormatCSV,
+		CombineCSVs:           true,
+		IncludeMissingMembers: true,
+	}
+}
+
+func parseExportOptions(form url.Values) (ExportOptions, error) {
+	includedFields := form["included_fields"]
+	if len(includedFields) == 0 {
+		includedFields = defaultFields
+	}
+	format, err := parseExportFormat(form.Get("format"))
+	if err != nil {
+		return ExportOptions{}, err
+	}
+
+	return ExportOptions{
+		IncludedFields:        includedFields,
+		Format:                format,
+		CombineCSVs:           xquery.ParseBool(form, "combine_csv", false),
+		IncludeMissingMembers: xquery.ParseBool(form, "include_missing_members", false),
+	}, nil
+}
+
+// Fields returns all fields which can be exported, with the included ones selected.
+func (o ExportOptions) Fields() []ExportField {
+	fields := make([]ExportField, len(fieldNames))
+	for i, field := range fieldNames {
+		fields[i] = ExportField{
+			Field:    field.Field,
+			Name:     field.Name,
+			Selected: slices.Contains(o.IncludedFields, field.Field),
+		}
+	}
+	return fields
+}
+
 type ExportVars struct {
+	ExportOptions
 	SelectedEventID string
 	Error           string
 }
@@ -58,7 +139,7 @@ func (h *handler) Export(w http.ResponseWriter, r *http.Request) {
 
 func (h *handler) renderExport(w http.ResponseWriter, r *http.Request, errorMessage string) {
 	if strings.HasPrefix(r.URL.Path, "/tracker/club/") {
-		h.renderTrackerClubExport(w, r, errorMessage)
+		h.renderTrackerClubExport(w, r, nil, errorMessage)
 		return
 	}
 
@@ -68,6 +149,7 @@ func (h *handler) renderExport(w http.ResponseWriter, r *http.Request, errorMess
 	eventID := query.Get("event")
 
 	if err := h.Templates().ExecuteTemplate(w, "export.gohtml", ExportVars{
+		ExportOptions:   defaultExportOptions(),
 		SelectedEventID: eventID,
 		Error:           errorMessage,
 	}); err != nil {
@@ -86,12 +168,14 @@ func (h *handler) DoExport(w http.ResponseWriter, r *http.Request) {
 
 	events := strings.TrimSpace(r.FormValue("events"))
 	eventIDs := r.Form["ids"]
-	includeMissingMembers := xquery.ParseBool(r.Form, "include_missing_members", false)
-	combineCSVs := xquery.ParseBool(r.Form, "combine_csv", false)
-	includedFields := r.Form["included_fields"]
-	if len(includedFields) == 0 {
-		includedFields = defaultFields
+	options, err := parseExportOptions(r.Form)
+	if err != nil {
+		h.renderExport(w, r, err.Error())
+		return
 	}
+	includeMissingMembers := options.IncludeMissingMembers
+	combineCSVs := options.CombineCSVs
+	includedFields := options.IncludedFields
 
 	slog.Info("Received export request",
 		slog.String("url", r.URL.String()),
@@ -99,6 +183,7 @@ func (h *handler) DoExport(w http.ResponseWriter, r *http.Request) {
 		slog.Bool("include_missing_members", includeMissingMembers),
 		slog.Bool("combine_csv", combineCSVs),
 		slog.Any("included_fields", includedFields),
+		slog.String("format", options.Format),
 	)
 
 	if events == "" && len(eventIDs) == 0 {
@@ -131,6 +216,11 @@ func (h *handler) DoExport(w http.ResponseWriter, r *http.Request) {
 
 	slog.InfoContext(ctx, "Fetched events", slog.Int("events", len(events)))
 
+	if options.Format == exportFormatXLSX {
+		h.exportWorkbook(ctx, w, campfireEvents, includeMissingMembers, includedFields)
+		return
+	}
+
 	var allRecords []Records
 	if combineCSVs {
 		records := [][]string{
@@ -255,7 +345,7 @@ func getRecords(event campfire.Event, includeMissingMembers bool, fields []strin
 }
 
 func eventURL(id string) string {
-	return fmt.Sprintf("https://campfire.nianticlabs.com/discover/meetup/%s", id)
+	return campfire.EventURL(id)
 }
 
 type Records struct {
@@ -300,6 +390,24 @@ func (h *handler) exportRecords(ctx context.Context, w http.ResponseWriter, allR
 	slog.InfoContext(ctx, "Export completed successfully", slog.Int("files", len(allRecords)))
 }
 
+func (h *handler) exportWorkbook(ctx context.Context, w http.ResponseWriter, events []campfire.Event, includeMissingMembers bool, fields []string) {
+	w.Header().Set("Content-Type", xlsx.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.xlsx", exportName()))
+	workbook := newWorkbookExport(w, fields, includeMissingMembers)
+	for _, event := range events {
+		if err := workbook.addCampfireEvent(event); err != nil {
+			slog.ErrorContext(ctx, "Failed to write workbook sheet", slog.String("event_id", event.ID), slog.Any("err", err))
+			return
+		}
+	}
+	if err := workbook.Close(); err != nil {
+		slog.ErrorContext(ctx, "Failed to close workbook", slog.Any("err", err))
+		return
+	}
+
+	slog.InfoContext(ctx, "Export completed successfully", slog.Int("sheets", len(events)+2))
+}
+
 func exportName() string {
 	return fmt.Sprintf("export_%s", time.Now().Format("20060102_150405"))
 }
@@ -0,0 +1,275 @@
+package tracker
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/topi314/campfire-tools/internal/xlsx"
+	"github.com/topi314/campfire-tools/internal/xquery"
+	"github.com/topi314/campfire-tools/server"
+	"github.com/topi314/campfire-tools/server/database"
+)
+
+// databaseExportFilter selects the imported events of a club to export, with the same filters as the club stats.
+type databaseExportFilter struct {
+	ClubID       string
+	From         time.Time
+	To           time.Time
+	OnlyCAEvents bool
+	EventCreator string
+	Categories   []string
+}
+
+func parseDatabaseExportFilter(clubID string, values url.Values, allCategories eventCategories) (databaseExportFilter, error) {
+	to := xquery.ParseTime(values, "to", time.Time{})
+	if !to.IsZero() {
+		to = to.Add(time.Hour*23 + time.Minute*59 + time.Second*59) // End of the day
+	}
+
+	categories, err := allCategories.parse(values["category"])
+	if err != nil {
+		return databaseExportFilter{}, err
+	}
+
+	return databaseExportFilter{
+		ClubID:       clubID,
+		From:         xquery.ParseTime(values, "from", time.Time{}),
+		To:           to,
+		OnlyCAEvents: xquery.ParseBool(values, "only-ca-events", false),
+		EventCreator: values.Get("event-creator"),
+		Categories:   categories,
+	}, nil
+}
+
+// streamDatabaseExport calls fn with the RSVPs of every event matching the filter, one event at a time.
+// Events without RSVPs are skipped, like in the Campfire export.
+func (h *handler) streamDatabaseExport(ctx context.Context, filter databaseExportFilter, fn func(rsvps []database.EventRSVPExport) error) error {
+	allCategories, err := h.getEventCategories(ctx)
+	if err != nil {
+		return err
+	}
+
+	var rsvps []database.EventRSVPExport
+	flush := func() error {
+		if len(rsvps) == 0 {
+			return nil
+		}
+		eventRSVPs := rsvps
+		rsvps = nil
+		if len(filter.Categories) > 0 && !slices.Contains(filter.Categories, allCategories.fromName(eventRSVPs[0].CampfireLiveEventName)) {
+			return nil
+		}
+		return fn(eventRSVPs)
+	}
+
+	if err = h.DB.StreamEventRSVPExports(ctx, filter.ClubID, filter.From, filter.To, filter.OnlyCAEvents, filter.EventCreator, func(rsvp database.EventRSVPExport) error {
+		if len(rsvps) > 0 && rsvps[0].Event.ID != rsvp.Event.ID {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		rsvps = append(rsvps, rsvp)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return flush()
+}
+
+// databaseExportWriter writes the events of a database export in the format of the export options.
+// Nothing is written before the first event, start is called right before it with the file that is written.
+type databaseExportWriter struct {
+	w        io.Writer
+	options  ExportOptions
+	start    func(file server.ExportFile)
+	cw       *csv.Writer
+	zw       *zip.Writer
+	workbook *workbookExport
+	events   int
+}
+
+func newDatabaseExportWriter(w io.Writer, options ExportOptions, start func(file server.ExportFile)) *databaseExportWriter {
+	return &databaseExportWriter{
+		w:       w,
+		options: options,
+		start:   start,
+	}
+}
+
+// File returns the type of the written file and the number of events written so far.
+func (e *databaseExportWriter) File() server.ExportFile {
+	switch {
+	case e.options.Format == exportFormatXLSX:
+		return server.ExportFile{Extension: "xlsx", ContentType: xlsx.ContentType, Events: e.events}
+	case e.options.CombineCSVs:
+		return server.ExportFile{Extension: "csv", ContentType: "text/csv; charset=utf-8", Events: e.events}
+	default:
+		return server.ExportFile{Extension: "zip", ContentType: "application/zip", Events: e.events}
+	}
+}
+
+func (e *databaseExportWriter) addEvent(rsvps []database.EventRSVPExport) error {
+	if e.events == 0 && e.start != nil {
+		e.start(e.File())
+	}
+	e.events++
+
+	if e.options.Format == exportFormatXLSX {
+		if e.workbook == nil {
+			e.workbook = newWorkbookExport(e.w, e.options.IncludedFields, e.options.IncludeMissingMembers)
+		}
+		return e.workbook.addDatabaseEvent(rsvps)
+	}
+
+	if e.options.CombineCSVs {
+		if e.cw == nil {
+			e.cw = csv.NewWriter(e.w)
+			if err := e.cw.Write(e.options.IncludedFields); err != nil {
+				return fmt.Errorf("failed to write CSV header: %w", err)
+			}
+		}
+	} else {
+		if e.zw == nil {
+			e.zw = zip.NewWriter(e.w)
+		}
+		filename := fmt.Sprintf("export_%s_%s.csv", rsvps[0].Event.ID, cleanFilename(rsvps[0].Event.Name))
+		f, err := e.zw.Create(filename)
+		if err != nil {
+			return fmt.Errorf("failed to create zip entry %q: %w", filename, err)
+		}
+		e.cw = csv.NewWriter(f)
+		if err = e.cw.Write(e.options.IncludedFields); err != nil {
+			return fmt.Errorf("failed to write CSV header: %w", err)
+		}
+	}
+
+	if err := e.cw.WriteAll(getDatabaseRecords(rsvps, e.options.IncludeMissingMembers, e.options.IncludedFields)); err != nil {
+		return fmt.Errorf("failed to write CSV records: %w", err)
+	}
+	return nil
+}
+
+// Close finishes the zip file or workbook. It does not close the underlying writer.
+func (e *databaseExportWriter) Close() error {
+	if e.zw != nil {
+		if err := e.zw.Close(); err != nil {
+			return fmt.Errorf("failed to close zip writer: %w", err)
+		}
+	}
+	if e.workbook != nil {
+		if err := e.workbook.Close(); err != nil {
+			return fmt.Errorf("failed to close workbook: %w", err)
+		}
+	}
+	return nil
+}
+
+// getDatabaseRecords is the database counterpart of getRecords. Members Campfire never returned a name for are stored without one.
+func getDatabaseRecords(rsvps []database.EventRSVPExport, includeMissingMembers bool, fields []string) [][]string {
+	var records [][]string
+	for _, rsvp := range rsvps {
+		if rsvp.Username == "" && rsvp.Member.DisplayName == "" && !includeMissingMembers {
+			continue
+		}
+		var record []string
+		for _, field := range fields {
+			switch field {
+			case FieldUserID:
+				record = append(record, rsvp.MemberID)
+			case FieldUsername:
+				record = append(record, rsvp.Username)
+			case FieldDisplayName:
+				record = append(record, rsvp.Member.DisplayName)
+			case FieldRSVPStatus:
+				record = append(record, rsvp.Status)
+			case FieldEventID:
+				record = append(record, rsvp.Event.ID)
+			case FieldEventName:
+				record = append(record, rsvp.Event.Name)
+			case FieldEventURL:
+				record = append(record, eventURL(rsvp.Event.ID))
+			case FieldEventTime:
+				record = append(record, rsvp.Time.Format(time.RFC3339))
+			case FieldEventClubID:
+				record = append(record, rsvp.ClubID)
+			case FieldEventCreatorUserID:
+				record = append(record, rsvp.CreatorID)
+			case FieldEventCreatorUsername:
+				record = append(record, rsvp.CreatorUsername)
+			case FieldEventCreatorDisplayName:
+				record = append(record, rsvp.CreatorDisplayName)
+			case FieldEventDiscordInterested:
+				record = append(record, strconv.Itoa(rsvp.DiscordInterested))
+			case FieldEventCreatedByCommunityAmbassador:
+				record = append(record, strconv.FormatBool(rsvp.Event.CreatedByCommunityAmbassador))
+			case FieldEventCampfireLiveEventID:
+				record = append(record, rsvp.CampfireLiveEventID)
+			case FieldEventCampfireLiveEventName:
+				record = append(record, rsvp.CampfireLiveEventName)
+			}
+		}
+
+		records = append(records, record)
+	}
+	return records
+}
+
+// newDatabaseExportEvent builds an ExportEvent from the RSVPs of one event. Badges are not stored in the database and are left empty.
+func newDatabaseExportEvent(club database.ClubWithCreator, rsvps []database.EventRSVPExport) ExportEvent {
+	event := rsvps[0]
+	exportEvent := ExportEvent{
+		ID:            event.Event.ID,
+		Name:          event.Event.Name,
+		Address:       event.Address,
+		CoverPhotoURL: event.CoverPhotoURL,
+		Details:       event.Details,
+		URL:           eventURL(event.Event.ID),
+		Time:          event.Time.UTC(),
+		EndTime:       event.EndTime.UTC(),
+		Club: ExportClub{
+			ID:                           club.Club.ID,
+			Name:                         club.Club.Name,
+			AvatarURL:                    club.Club.AvatarURL,
+			CreatedByCommunityAmbassador: club.Club.CreatedByCommunityAmbassador,
+			Creator: ExportMember{
+				ID:          club.Member.ID,
+				Username:    club.Member.Username,
+				DisplayName: club.Member.DisplayName,
+				AvatarURL:   club.Member.AvatarURL,
+			},
+		},
+		Creator: ExportMember{
+			ID:          event.CreatorID,
+			Username:    event.CreatorUsername,
+			DisplayName: event.CreatorDisplayName,
+		},
+		DiscordInterested:            event.DiscordInterested,
+		CreatedByCommunityAmbassador: event.Event.CreatedByCommunityAmbassador,
+		CampfireLiveEvent: ExportCampfireLiveEvent{
+			ID:   event.CampfireLiveEventID,
+			Name: event.CampfireLiveEventName,
+		},
+	}
+
+	for _, rsvp := range rsvps {
+		exportEvent.Members = append(exportEvent.Members, ExportRSVPMember{
+			ExportMember: ExportMember{
+				ID:          rsvp.MemberID,
+				Username:    rsvp.Username,
+				DisplayName: rsvp.Member.DisplayName,
+				AvatarURL:   rsvp.Member.AvatarURL,
+			},
+			RSVPStatus: rsvp.Status,
+		})
+	}
+
+	return exportEvent
+}
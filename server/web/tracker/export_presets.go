@@ -0,0 +1,253 @@
+package tracker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/topi314/campfire-tools/internal/xquery"
+	"github.com/topi314/campfire-tools/internal/xtime"
+	"github.com/topi314/campfire-tools/server"
+	"github.com/topi314/campfire-tools/server/auth"
+	"github.com/topi314/campfire-tools/server/database"
+	"github.com/topi314/campfire-tools/server/web/models"
+)
+
+const exportPresetMaxNameLength = 100
+
+// ExportEventRange selects the events of a preset relative to the time it is run.
+type ExportEventRange struct {
+	Value string
+	Name  string
+}
+
+var exportEventRanges = []ExportEventRange{
+	{Value: "last-7-days", Name: "Last 7 Days"},
+	{Value: "last-30-days", Name: "Last 30 Days"},
+	{Value: "this-month", Name: "This Month"},
+	{Value: "last-month", Name: "Last Month"},
+	{Value: "this-quarter", Name: "This Quarter"},
+	{Value: "last-quarter", Name: "Last Quarter"},
+	{Value: "all", Name: "All Events"},
+}
+
+func exportEventRangeName(value string) string {
+	for _, eventRange := range exportEventRanges {
+		if eventRange.Value == value {
+			return eventRange.Name
+		}
+	}
+	return ""
+}
+
+// getExportEventRange returns the from and to time of the event range at the given time. A zero time means no limit.
+func getExportEventRange(value string, now time.Time) (time.Time, time.Time, error) {
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	switch value {
+	case "last-7-days":
+		return today.AddDate(0, 0, -7), now, nil
+	case "last-30-days":
+		return today.AddDate(0, 0, -30), now, nil
+	case "this-month":
+		return thisMonth, thisMonth.AddDate(0, 1, 0).Add(-time.Second), nil
+	case "last-month":
+		return thisMonth.AddDate(0, -1, 0), thisMonth.Add(-time.Second), nil
+	case "this-quarter":
+		from, to := xtime.GetQuarterRange(now)
+		return from, to, nil
+	case "last-quarter":
+		thisQuarter, _ := xtime.GetQuarterRange(now)
+		from, to := xtime.GetQuarterRange(thisQuarter.AddDate(0, 0, -1))
+		return from, to, nil
+	case "all":
+		return time.Time{}, time.Time{}, nil
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("invalid event range: %s", value)
+}
+
+func newExportPresets(presets []database.ExportPreset) []models.ExportPreset {
+	exportPresets := make([]models.ExportPreset, len(presets))
+	for i, preset := range presets {
+		exportPresets[i] = models.NewExportPreset(preset, exportEventRangeName(preset.EventRange))
+	}
+	return exportPresets
+}
+
+func exportPresetFilter(preset database.ExportPreset, from time.Time, to time.Time) databaseExportFilter {
+	return databaseExportFilter{
+		ClubID:       preset.ClubID,
+		From:         from,
+		To:           to,
+		OnlyCAEvents: preset.OnlyCAEvents,
+		EventCreator: preset.EventCreator,
+		Categories:   preset.Categories,
+	}
+}
+
+func exportPresetOptions(preset database.ExportPreset) ExportOptions {
+	return ExportOptions{
+		IncludedFields:        preset.IncludedFields,
+		Format:                preset.Format,
+		CombineCSVs:           preset.CombineCSV,
+		IncludeMissingMembers: preset.IncludeMissingMembers,
+	}
+}
+
+// runTrackerClubExportPreset exports the events of a preset with an event range, or shows the export form with the preset's options.
+func (h *handler) runTrackerClubExportPreset(w http.ResponseWriter, r *http.Request, presetID string) {
+	ctx := r.Context()
+	session := auth.GetSession(r)
+
+	clubID := r.PathValue("club_id")
+	id, err := strconv.Atoi(presetID)
+	if err != nil {
+		h.NotFound(w, r)
+		return
+	}
+
+	preset, err := h.DB.GetExportPreset(ctx, id, session.UserID, clubID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			h.NotFound(w, r)
+			return
+		}
+		slog.ErrorContext(ctx, "Failed to get export preset", slog.Int("preset_id", id), slog.Any("err", err))
+		http.Error(w, "Failed to get export preset: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if preset.EventRange == "" {
+		h.renderTrackerClubExport(w, r, preset, "")
+		return
+	}
+
+	from, to, err := getExportEventRange(preset.EventRange, time.Now())
+	if err != nil {
+		h.renderTrackerClubExport(w, r, preset, err.Error())
+		return
+	}
+
+	h.exportDatabase(w, r, exportPresetFilter(*preset, from, to), exportPresetOptions(*preset), preset)
+}
+
+// exportPreset is the server.PresetExporter used by scheduled exports.
+func (h *handler) exportPreset(ctx context.Context, preset database.ExportPreset, now time.Time, w io.Writer) (server.ExportFile, error) {
+	if preset.EventRange == "" {
+		return server.ExportFile{}, errors.New("the preset has no event range")
+	}
+
+	from, to, err := getExportEventRange(preset.EventRange, now)
+	if err != nil {
+		return server.ExportFile{}, err
+	}
+
+	ew := newDatabaseExportWriter(w, exportPresetOptions(preset), nil)
+	if err = h.streamDatabaseExport(ctx, exportPresetFilter(preset, from, to), ew.addEvent); err != nil {
+		return server.ExportFile{}, err
+	}
+	if ew.events == 0 {
+		return ew.File(), nil
+	}
+
+	return ew.File(), ew.Close()
+}
+
+// PostTrackerClubExportPreset saves the options of the export form as a preset, replacing a preset with the same name.
+func (h *handler) PostTrackerClubExportPreset(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	session := auth.GetSession(r)
+
+	if err := r.ParseForm(); err != nil {
+		h.renderTrackerClubExport(w, r, nil, "Failed to parse form data: "+err.Error())
+		return
+	}
+
+	clubID := r.PathValue("club_id")
+	name := strings.TrimSpace(r.Form.Get("preset_name"))
+	eventRange := r.Form.Get("preset_event_range")
+
+	if name == "" {
+		h.renderTrackerClubExport(w, r, nil, "Enter a name for the preset")
+		return
+	}
+	if len(name) > exportPresetMaxNameLength {
+		h.renderTrackerClubExport(w, r, nil, fmt.Sprintf("Preset names can be at most %d characters long", exportPresetMaxNameLength))
+		return
+	}
+	if eventRange != "" {
+		if _, _, err := getExportEventRange(eventRange, time.Now()); err != nil {
+			h.renderTrackerClubExport(w, r, nil, err.Error())
+			return
+		}
+	}
+
+	options, err := parseExportOptions(r.Form)
+	if err != nil {
+		h.renderTrackerClubExport(w, r, nil, err.Error())
+		return
+	}
+	allCategories, err := h.getEventCategories(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch event categories", slog.Any("err", err))
+		h.renderTrackerClubExport(w, r, nil, "Failed to fetch event categories")
+		return
+	}
+	categories, err := allCategories.parse(r.Form["category"])
+	if err != nil {
+		h.renderTrackerClubExport(w, r, nil, err.Error())
+		return
+	}
+	if categories == nil {
+		categories = []string{}
+	}
+
+	if _, err = h.DB.UpsertExportPreset(ctx, database.ExportPreset{
+		UserID:                session.UserID,
+		ClubID:                clubID,
+		Name:                  name,
+		IncludedFields:        options.IncludedFields,
+		Format:                options.Format,
+		CombineCSV:            options.CombineCSVs,
+		IncludeMissingMembers: options.IncludeMissingMembers,
+		EventRange:            eventRange,
+		OnlyCAEvents:          xquery.ParseBool(r.Form, "only-ca-events", false),
+		EventCreator:          r.Form.Get("event-creator"),
+		Categories:            categories,
+	}); err != nil {
+		slog.ErrorContext(ctx, "Failed to save export preset", slog.String("club_id", clubID), slog.Any("err", err))
+		h.renderTrackerClubExport(w, r, nil, "Failed to save export preset")
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/tracker/club/%s/export", clubID), http.StatusSeeOther)
+}
+
+func (h *handler) TrackerClubExportPresetDelete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	session := auth.GetSession(r)
+
+	clubID := r.PathValue("club_id")
+	id, err := strconv.Atoi(r.PathValue("preset_id"))
+	if err != nil {
+		h.NotFound(w, r)
+		return
+	}
+
+	if err = h.DB.DeleteExportPreset(ctx, id, session.UserID, clubID); err != nil {
+		slog.ErrorContext(ctx, "Failed to delete export preset", slog.String("club_id", clubID), slog.Any("err", err))
+		http.Error(w, "Failed to delete export preset", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/tracker/club/%s/export", clubID), http.StatusSeeOther)
+}
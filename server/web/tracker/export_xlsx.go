@@ -0,0 +1,194 @@
+package tracker
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/topi314/campfire-tools/internal/xlsx"
+	"github.com/topi314/campfire-tools/server/campfire"
+	"github.com/topi314/campfire-tools/server/database"
+	"github.com/topi314/campfire-tools/server/web/models"
+)
+
+const (
+	exportFormatCSV  = "csv"
+	exportFormatXLSX = "xlsx"
+)
+
+func parseExportFormat(format string) (string, error) {
+	switch format {
+	case "", exportFormatCSV:
+		return exportFormatCSV, nil
+	case exportFormatXLSX:
+		return exportFormatXLSX, nil
+	}
+	return "", fmt.Errorf("invalid format: %s", format)
+}
+
+// workbookRSVP is an RSVP of an exported event, whether it was fetched from Campfire or read from the database.
+type workbookRSVP struct {
+	MemberID    string
+	Username    string
+	DisplayName string
+	Status      string
+}
+
+type workbookEvent struct {
+	ID       string
+	Name     string
+	Time     time.Time
+	Accepted int
+	CheckIns int
+}
+
+type workbookMember struct {
+	ID          string
+	Username    string
+	DisplayName string
+	Accepted    int
+	CheckIns    int
+}
+
+// workbookExport writes an xlsx export with one sheet per event, followed by a summary sheet and a member sheet.
+// Event sheets are written as events are added, only the summary and member counts are kept in memory.
+type workbookExport struct {
+	xw                    *xlsx.Writer
+	fields                []string
+	includeMissingMembers bool
+	events                []workbookEvent
+	members               map[string]*workbookMember
+}
+
+func newWorkbookExport(w io.Writer, fields []string, includeMissingMembers bool) *workbookExport {
+	return &workbookExport{
+		xw:                    xlsx.NewWriter(w),
+		fields:                fields,
+		includeMissingMembers: includeMissingMembers,
+		members:               make(map[string]*workbookMember),
+	}
+}
+
+func (e *workbookExport) addEvent(id string, name string, eventTime time.Time, records [][]string, rsvps []workbookRSVP) error {
+	sheet, err := e.xw.NewSheet(eventTime.Format(time.DateOnly) + " " + name)
+	if err != nil {
+		return err
+	}
+	if err = sheet.WriteRows(append([][]string{e.fields}, records...)); err != nil {
+		return err
+	}
+
+	event := workbookEvent{
+		ID:   id,
+		Name: name,
+		Time: eventTime,
+	}
+	for _, rsvp := range rsvps {
+		var accepted, checkIns int
+		switch rsvp.Status {
+		case "CHECKED_IN":
+			accepted, checkIns = 1, 1
+		case "ACCEPTED":
+			accepted = 1
+		default:
+			continue
+		}
+		event.Accepted += accepted
+		event.CheckIns += checkIns
+
+		if rsvp.Username == "" && rsvp.DisplayName == "" && !e.includeMissingMembers {
+			continue
+		}
+		member, ok := e.members[rsvp.MemberID]
+		if !ok {
+			member = &workbookMember{
+				ID:          rsvp.MemberID,
+				Username:    rsvp.Username,
+				DisplayName: rsvp.DisplayName,
+			}
+			e.members[rsvp.MemberID] = member
+		}
+		member.Accepted += accepted
+		member.CheckIns += checkIns
+	}
+	e.events = append(e.events, event)
+
+	return nil
+}
+
+func (e *workbookExport) addCampfireEvent(event campfire.Event) error {
+	rsvps := make([]workbookRSVP, len(event.RSVPStatuses))
+	for i, rsvpStatus := range event.RSVPStatuses {
+		member, _ := campfire.FindMember(rsvpStatus.UserID, event)
+		rsvps[i] = workbookRSVP{
+			MemberID:    rsvpStatus.UserID,
+			Username:    member.Username,
+			DisplayName: member.DisplayName,
+			Status:      rsvpStatus.RSVPStatus,
+		}
+	}
+
+	return e.addEvent(event.ID, event.Name, event.EventTime, getRecords(event, e.includeMissingMembers, e.fields), rsvps)
+}
+
+func (e *workbookExport) addDatabaseEvent(eventRSVPs []database.EventRSVPExport) error {
+	rsvps := make([]workbookRSVP, len(eventRSVPs))
+	for i, rsvp := range eventRSVPs {
+		rsvps[i] = workbookRSVP{
+			MemberID:    rsvp.MemberID,
+			Username:    rsvp.Username,
+			DisplayName: rsvp.Member.DisplayName,
+			Status:      rsvp.Status,
+		}
+	}
+
+	event := eventRSVPs[0]
+	return e.addEvent(event.Event.ID, event.Event.Name, event.Time, getDatabaseRecords(eventRSVPs, e.includeMissingMembers, e.fields), rsvps)
+}
+
+// Close writes the summary and member sheets and finishes the workbook.
+func (e *workbookExport) Close() error {
+	summary, err := e.xw.NewSheet("Summary")
+	if err != nil {
+		return err
+	}
+	if err = summary.WriteRow("Event ID", "Event Name", "Event Time", "Accepted", "Check-Ins", "Check-In Rate (%)"); err != nil {
+		return err
+	}
+	var totalAccepted, totalCheckIns int
+	for _, event := range e.events {
+		totalAccepted += event.Accepted
+		totalCheckIns += event.CheckIns
+		if err = summary.WriteRow(event.ID, event.Name, event.Time.Format(time.RFC3339), event.Accepted, event.CheckIns, models.CalcCheckInRate(event.Accepted, event.CheckIns)); err != nil {
+			return err
+		}
+	}
+	if err = summary.WriteRow("Total", fmt.Sprintf("%d Events", len(e.events)), "", totalAccepted, totalCheckIns, models.CalcCheckInRate(totalAccepted, totalCheckIns)); err != nil {
+		return err
+	}
+
+	members := slices.SortedFunc(maps.Values(e.members), func(a, b *workbookMember) int {
+		return cmp.Or(
+			cmp.Compare(b.CheckIns, a.CheckIns),
+			cmp.Compare(b.Accepted, a.Accepted),
+			cmp.Compare(a.Username, b.Username),
+		)
+	})
+	memberSheet, err := e.xw.NewSheet("Members")
+	if err != nil {
+		return err
+	}
+	if err = memberSheet.WriteRow("User ID", "Username", "Display Name", "Accepted", "Check-Ins", "Check-In Rate (%)"); err != nil {
+		return err
+	}
+	for _, member := range members {
+		if err = memberSheet.WriteRow(member.ID, member.Username, member.DisplayName, member.Accepted, member.CheckIns, models.CalcCheckInRate(member.Accepted, member.CheckIns)); err != nil {
+			return err
+		}
+	}
+
+	return e.xw.Close()
+}
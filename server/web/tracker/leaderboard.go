@@ -0,0 +1,82 @@
+package tracker
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/topi314/campfire-tools/internal/xtime"
+	"github.com/topi314/campfire-tools/server/database"
+	"github.com/topi314/campfire-tools/server/web/models"
+)
+
+type TrackerLeaderboardVars struct {
+	Quarter             string
+	QuarterName         string
+	PreviousQuarterName string
+	Quarters            []xtime.Quarter
+	Clubs               []LeaderboardClub
+}
+
+type LeaderboardClub struct {
+	models.Club
+	Accepted           int
+	CheckIns           int
+	CheckInRate        float64
+	CACheckIns         int
+	PreviousCACheckIns int
+	League             string
+	Growth             float64
+	// HasGrowth is false if the club had no CA check-ins in the previous quarter.
+	HasGrowth bool
+}
+
+func (h *handler) TrackerLeaderboard(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	from, to := xtime.GetRangeFromQuarter(query.Get("quarter"))
+	previousFrom, _ := xtime.GetQuarterRange(from.AddDate(0, -3, 0))
+
+	clubs, err := h.DB.GetClubLeaderboard(ctx, from, to, previousFrom)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch club leaderboard", slog.Time("from", from), slog.Time("to", to), slog.Any("err", err))
+		http.Error(w, "Failed to fetch club leaderboard: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	leaderboardClubs := make([]LeaderboardClub, len(clubs))
+	for i, club := range clubs {
+		leaderboardClubs[i] = LeaderboardClub{
+			Club:               models.NewClub(database.ClubWithCreator{Club: club.Club}),
+			Accepted:           club.Accepted,
+			CheckIns:           club.CheckIns,
+			CheckInRate:        models.CalcCheckInRate(club.Accepted, club.CheckIns),
+			CACheckIns:         club.CACheckIns,
+			PreviousCACheckIns: club.PreviousCACheckIns,
+		}
+		leaderboardClubs[i].Growth, leaderboardClubs[i].HasGrowth = models.CalcGrowth(club.PreviousCACheckIns, club.CACheckIns)
+		if league := models.GetLeague(club.CACheckIns); league != nil {
+			leaderboardClubs[i].League = league.Name
+		}
+	}
+
+	if err = h.Templates().ExecuteTemplate(w, "tracker_leaderboard.gohtml", TrackerLeaderboardVars{
+		Quarter:             quarterValue(from),
+		QuarterName:         quarterName(from),
+		PreviousQuarterName: quarterName(previousFrom),
+		Quarters:            xtime.GetQuarters(),
+		Clubs:               leaderboardClubs,
+	}); err != nil {
+		slog.ErrorContext(ctx, "Failed to render tracker leaderboard template", slog.Any("err", err))
+	}
+}
+
+func quarterValue(t time.Time) string {
+	return fmt.Sprintf("q%d-%d", (int(t.Month())-1)/3+1, t.Year())
+}
+
+func quarterName(t time.Time) string {
+	return fmt.Sprintf("Q%d %d", (int(t.Month())-1)/3+1, t.Year())
+}
@@ -6,7 +6,9 @@ import (
 	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/topi314/campfire-tools/server/database"
 	"github.com/topi314/campfire-tools/server/web/models"
 )
 
@@ -21,6 +23,7 @@ type TrackerMemberVars struct {
 	models.ImportedMember
 	CheckInEventsByClub  []models.ClubMemberEvents
 	AcceptedEventsByClub []models.ClubMemberEvents
+	Reliability          models.Reliability
 }
 
 func (h *handler) TrackerMembers(w http.ResponseWriter, r *http.Request) {
@@ -88,7 +91,16 @@ func (h *handler) TrackerMember(w http.ResponseWriter, r *http.Request) {
 		},
 		CheckInEventsByClub:  models.GroupEventsByClub(checkInEvents, 32),
 		AcceptedEventsByClub: models.GroupEventsByClub(acceptedEvents, 32),
+		Reliability:          models.NewReliability(clubEvents(checkInEvents), clubEvents(acceptedEvents), time.Now()),
 	}); err != nil {
 		slog.ErrorContext(ctx, "Failed to render tracker member template", slog.String("member_id", memberID), slog.Any("err", err))
 	}
 }
+
+func clubEvents(rows []database.EventWithClub) []database.Event {
+	events := make([]database.Event, len(rows))
+	for i, row := range rows {
+		events[i] = row.Event
+	}
+	return events
+}
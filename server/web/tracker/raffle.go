@@ -7,16 +7,20 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
-	"math/rand/v2"
 	"net/http"
+	"net/url"
 	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
+	"github.com/topi314/campfire-tools/internal/xpgtype"
 	"github.com/topi314/campfire-tools/internal/xquery"
+	"github.com/topi314/campfire-tools/internal/xrand"
+	"github.com/topi314/campfire-tools/internal/xtime"
 	"github.com/topi314/campfire-tools/server/auth"
 	"github.com/topi314/campfire-tools/server/campfire"
 	"github.com/topi314/campfire-tools/server/database"
@@ -25,6 +29,7 @@ import (
 
 type RaffleVars struct {
 	Raffles         []models.Raffle
+	Rewards         []models.Reward
 	SelectedEventID string
 	Error           string
 }
@@ -36,6 +41,10 @@ type RaffleResultVars struct {
 	RerunRaffleURL  string
 	AddEventsURL    string
 	Error           string
+	PrizeTiers      []models.RafflePrizeTier
+	RewardName      string
+	DisplayURL      string
+	Drawn           bool
 	Winners         []models.Winner
 	PastWinners     []models.Winner
 	PastWinnersOpen bool
@@ -79,6 +88,11 @@ func (h *handler) RunRaffle(w http.ResponseWriter, r *http.Request) {
 	winnerCount := xquery.ParseInt(r.Form, "winner_count", 1)
 	onlyCheckedIn := xquery.ParseBool(r.Form, "only_checked_in", false)
 	singleEntry := xquery.ParseBool(r.Form, "single_entry", false)
+	weighting := database.RaffleWeighting(xquery.ParseString(r.Form, "weighting", string(database.RaffleWeightingNone)))
+	bonusMinCheckIns := xquery.ParseInt(r.Form, "bonus_min_check_ins", 0)
+	bonusTickets := xquery.ParseInt(r.Form, "bonus_tickets", 0)
+	maxTickets := xquery.ParseInt(r.Form, "max_tickets", 0)
+	minOneTicket := xquery.ParseBool(r.Form, "min_one_ticket", false)
 
 	slog.InfoContext(ctx, "Received raffle request",
 		slog.String("url", r.URL.String()),
@@ -86,13 +100,47 @@ func (h *handler) RunRaffle(w http.ResponseWriter, r *http.Request) {
 		slog.Int("winner_count", winnerCount),
 		slog.Bool("only_checked_in", onlyCheckedIn),
 		slog.Bool("single_entry", singleEntry),
+		slog.String("weighting", string(weighting)),
+		slog.Int("bonus_min_check_ins", bonusMinCheckIns),
+		slog.Int("bonus_tickets", bonusTickets),
+		slog.Int("max_tickets", maxTickets),
+		slog.Bool("min_one_ticket", minOneTicket),
 	)
 
+	if weighting != database.RaffleWeightingNone && weighting != database.RaffleWeightingCheckIns {
+		h.renderRaffle(w, r, nil, fmt.Sprintf("Unknown weighting %q", weighting))
+		return
+	}
+	if bonusMinCheckIns < 0 || bonusTickets < 0 || maxTickets < 0 {
+		h.renderRaffle(w, r, nil, "Bonus and ticket cap values must not be negative")
+		return
+	}
+
 	if events == "" && len(eventIDs) == 0 {
 		h.renderRaffle(w, r, nil, "Missing 'events' parameter")
 		return
 	}
 
+	session := auth.GetSession(r)
+
+	rewardID, err := h.parseRaffleReward(ctx, r.FormValue("reward"), session.UserID)
+	if err != nil {
+		h.renderRaffle(w, r, nil, err.Error())
+		return
+	}
+
+	prizeTiers, err := h.parseRafflePrizeTiers(ctx, r.Form, session.UserID)
+	if err != nil {
+		h.renderRaffle(w, r, nil, err.Error())
+		return
+	}
+	if len(prizeTiers) > 0 {
+		winnerCount = 0
+		for _, tier := range prizeTiers {
+			winnerCount += tier.Count
+		}
+	}
+
 	var allEvents []string
 	for _, event := range strings.Split(events, "\n") {
 		event = strings.TrimSpace(event)
@@ -131,23 +179,32 @@ func (h *handler) RunRaffle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	session := auth.GetSession(r)
+	seed := xrand.NewSeed()
 
-	raffle := database.Raffle{
-		UserID:        session.UserID,
-		Events:        allEventIDs,
-		WinnerCount:   winnerCount,
-		OnlyCheckedIn: onlyCheckedIn,
-		SingleEntry:   singleEntry,
+	var raffleClubID *string
+	if clubID != "" {
+		raffleClubID = &clubID
 	}
 
-	winners, err := h.raffleWinners(ctx, raffle, nil)
-	if err != nil {
-		slog.ErrorContext(ctx, "Failed to run raffle", slog.Any("err", err))
-		h.renderRaffle(w, r, nil, "Failed to run raffle: "+err.Error())
-		return
+	raffle := database.Raffle{
+		UserID:           session.UserID,
+		Events:           allEventIDs,
+		WinnerCount:      winnerCount,
+		OnlyCheckedIn:    onlyCheckedIn,
+		SingleEntry:      singleEntry,
+		CreatedAt:        time.Now(),
+		Weighting:        weighting,
+		BonusMinCheckIns: bonusMinCheckIns,
+		BonusTickets:     bonusTickets,
+		MaxTickets:       maxTickets,
+		MinOneTicket:     minOneTicket,
+		Seed:             seed,
+		SeedCommitment:   xrand.SeedCommitment(seed),
+		ClubID:           raffleClubID,
+		RewardID:         rewardID,
 	}
 
+	// Only the commitment of the seed is shown now, the winners are drawn in a separate request like every rerun
 	raffleID, err := h.DB.InsertRaffle(ctx, raffle)
 	if err != nil {
 		slog.ErrorContext(ctx, "Failed to insert raffle into database", slog.Any("err", err))
@@ -155,9 +212,12 @@ func (h *handler) RunRaffle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err = h.processRaffleWinners(ctx, raffleID, winners, true); err != nil {
-		slog.ErrorContext(ctx, "Failed to process raffle winners", slog.Any("err", err))
-		h.renderRaffle(w, r, nil, "Failed to process raffle winners: "+err.Error())
+	for i := range prizeTiers {
+		prizeTiers[i].RaffleID = raffleID
+	}
+	if err = h.DB.InsertRafflePrizeTiers(ctx, prizeTiers); err != nil {
+		slog.ErrorContext(ctx, "Failed to insert raffle prize tiers into database", slog.Any("err", err))
+		h.renderRaffle(w, r, nil, "Failed to create raffle prize tiers: "+err.Error())
 		return
 	}
 
@@ -203,6 +263,23 @@ func (h *handler) RerunRaffle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var rawQuery string
+	if pastWinnersOpen {
+		rawQuery = "past-winners=true"
+	}
+
+	// Raffles created before seeds were committed only get a seed committed, their winners are drawn with the next request
+	if raffle.Seed == "" {
+		seed := xrand.NewSeed()
+		if err = h.DB.CommitRaffleSeed(ctx, raffleID, seed, xrand.SeedCommitment(seed)); err != nil {
+			slog.ErrorContext(ctx, "Failed to commit raffle seed", slog.Any("err", err))
+			h.renderRaffleResult(w, r, *raffle, clubID, "Failed to commit raffle seed: "+err.Error())
+			return
+		}
+		redirectRaffle(w, r, raffleID, clubID, rawQuery)
+		return
+	}
+
 	pastWinners, err := h.DB.GetRaffleWinners(ctx, raffleID)
 	if err != nil {
 		slog.ErrorContext(ctx, "Failed to get past raffle winners from database", slog.Any("err", err))
@@ -210,23 +287,39 @@ func (h *handler) RerunRaffle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	winners, err := h.raffleWinners(ctx, *raffle, pastWinners)
+	prizeTiers, err := h.DB.GetRafflePrizeTiers(ctx, raffleID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get raffle prize tiers from database", slog.Any("err", err))
+		h.renderRaffleResult(w, r, *raffle, clubID, "Failed to get raffle prize tiers: "+err.Error())
+		return
+	}
+
+	winnerCount := raffle.WinnerCount
+	slots := rafflePrizeTierSlots(prizeTiers, pastWinners)
+	if len(prizeTiers) > 0 {
+		winnerCount = len(slots)
+	}
+
+	draw, winners, err := h.drawRaffle(ctx, *raffle, pastWinners, winnerCount)
 	if err != nil {
 		slog.ErrorContext(ctx, "Failed to rerun raffle", slog.Any("err", err))
 		h.renderRaffleResult(w, r, *raffle, clubID, "Failed to rerun raffle: "+err.Error())
 		return
 	}
 
-	if err = h.processRaffleWinners(ctx, raffleID, winners, false); err != nil {
+	if err = h.recordRaffleDraw(ctx, draw); err != nil {
+		slog.ErrorContext(ctx, "Failed to record raffle draw", slog.Any("err", err))
+		h.renderRaffleResult(w, r, *raffle, clubID, "Failed to record raffle draw: "+err.Error())
+		return
+	}
+
+	if err = h.processRaffleWinners(ctx, raffleID, winners, slots); err != nil {
 		slog.ErrorContext(ctx, "Failed to process raffle winners", slog.Any("err", err))
 		h.renderRaffleResult(w, r, *raffle, clubID, "Failed to process raffle winners: "+err.Error())
 		return
 	}
 
-	var rawQuery string
-	if pastWinnersOpen {
-		rawQuery = "past-winners=true"
-	}
+	h.RaffleUpdates.Publish(raffleID)
 
 	redirectRaffle(w, r, raffleID, clubID, rawQuery)
 }
@@ -280,6 +373,38 @@ func (h *handler) GetRaffle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	drawCount, err := h.DB.GetRaffleDrawCount(ctx, raffleID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get raffle draw count from database", slog.Any("err", err))
+		h.renderRaffleResult(w, r, *raffle, clubID, "Failed to get raffle draw count: "+err.Error())
+		return
+	}
+
+	prizeTiers, err := h.DB.GetRafflePrizeTiers(ctx, raffleID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get raffle prize tiers from database", slog.Any("err", err))
+		h.renderRaffleResult(w, r, *raffle, clubID, "Failed to get raffle prize tiers: "+err.Error())
+		return
+	}
+
+	var rewardName string
+	if raffle.RewardID != nil {
+		reward, err := h.DB.GetReward(ctx, *raffle.RewardID, session.UserID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			slog.ErrorContext(ctx, "Failed to get raffle reward from database", slog.Any("err", err))
+			h.renderRaffleResult(w, r, *raffle, clubID, "Failed to get raffle reward: "+err.Error())
+			return
+		}
+		if reward != nil {
+			rewardName = reward.Name
+		}
+	}
+
+	prizeTierModels := make([]models.RafflePrizeTier, 0, len(prizeTiers))
+	for _, tier := range prizeTiers {
+		prizeTierModels = append(prizeTierModels, models.NewRafflePrizeTier(tier))
+	}
+
 	var winners []models.Winner
 	var pastWinners []models.Winner
 	for _, winner := range allWinners {
@@ -306,6 +431,10 @@ func (h *handler) GetRaffle(w http.ResponseWriter, r *http.Request) {
 		ClubID:          clubID,
 		RerunRaffleURL:  r.URL.Path,
 		AddEventsURL:    addEventsURL,
+		PrizeTiers:      prizeTierModels,
+		RewardName:      rewardName,
+		DisplayURL:      fmt.Sprintf("/raffle/%d/display?%s", raffleID, url.Values{"token": {h.RaffleDisplayToken(raffleID)}}.Encode()),
+		Drawn:           drawCount > 0 || len(allWinners) > 0,
 		Winners:         winners,
 		PastWinners:     pastWinners,
 		PastWinnersOpen: pastWinnersOpen,
@@ -315,10 +444,75 @@ func (h *handler) GetRaffle(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func (h *handler) raffleWinners(ctx context.Context, raffle database.Raffle, pastWinners []database.RaffleWinnerWithMember) ([]campfire.Member, error) {
+// raffleEntrant is a member taking part in a raffle with the number of tickets they hold.
+type raffleEntrant struct {
+	Member  campfire.Member
+	Tickets int
+}
+
+// drawRaffle draws count winners of the raffle with its committed seed and returns the draw to record.
+func (h *handler) drawRaffle(ctx context.Context, raffle database.Raffle, pastWinners []database.RaffleWinnerWithMember, count int) (database.RaffleDraw, []campfire.Member, error) {
+	entrants, err := h.raffleEntrants(ctx, raffle, pastWinners)
+	if err != nil {
+		return database.RaffleDraw{}, nil, err
+	}
+
+	// Sort the entrants so the draw only depends on the snapshot and the seed
+	slices.SortFunc(entrants, func(a, b raffleEntrant) int {
+		return strings.Compare(a.Member.ID, b.Member.ID)
+	})
+
+	snapshot := make([]database.RaffleDrawEntrant, 0, len(entrants))
+	for _, entrant := range entrants {
+		snapshot = append(snapshot, database.RaffleDrawEntrant{
+			MemberID:    entrant.Member.ID,
+			DisplayName: entrant.Member.DisplayName,
+			Tickets:     entrant.Tickets,
+		})
+	}
+
+	if raffle.Seed == "" {
+		return database.RaffleDraw{}, nil, errors.New("the raffle has no committed seed")
+	}
+
+	winnerIDs := drawRaffleWinners(snapshot, count, raffle.Seed)
+	winners := make([]campfire.Member, 0, len(winnerIDs))
+	for _, winnerID := range winnerIDs {
+		i := slices.IndexFunc(entrants, func(entrant raffleEntrant) bool {
+			return entrant.Member.ID == winnerID
+		})
+		winners = append(winners, entrants[i].Member)
+	}
+
+	return database.RaffleDraw{
+		RaffleID:       raffle.ID,
+		SeedCommitment: raffle.SeedCommitment,
+		Seed:           raffle.Seed,
+		WinnerCount:    count,
+		Entrants:       xpgtype.NewJSON(snapshot),
+		Winners:        winnerIDs,
+	}, winners, nil
+}
+
+// recordRaffleDraw stores the draw and commits a new seed for the next draw of the raffle.
+func (h *handler) recordRaffleDraw(ctx context.Context, draw database.RaffleDraw) error {
+	nextSeed := xrand.NewSeed()
+	return h.DB.InsertRaffleDraw(ctx, draw, nextSeed, xrand.SeedCommitment(nextSeed))
+}
+
+func (h *handler) raffleEntrants(ctx context.Context, raffle database.Raffle, pastWinners []database.RaffleWinnerWithMember) ([]raffleEntrant, error) {
+	var exclusions raffleExclusions
+	if raffle.ClubID != nil {
+		var err error
+		exclusions, err = h.clubRaffleExclusions(ctx, *raffle.ClubID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	eg, egCtx := errgroup.WithContext(ctx)
-	var eventIDs []string
-	var members []campfire.Member
+	var entrants []raffleEntrant
+	var clubIDs []string
 	var mu sync.Mutex
 	for _, eventID := range raffle.Events {
 		eg.Go(func() error {
@@ -330,10 +524,12 @@ func (h *handler) raffleWinners(ctx context.Context, raffle database.Raffle, pas
 			mu.Lock()
 			defer mu.Unlock()
 
-			eventIDs = append(eventIDs, event.ID)
-
-			if len(event.RSVPStatuses) == 0 {
-				return nil
+			clubID := event.ClubID
+			if clubID == "" {
+				clubID = event.Club.ID
+			}
+			if clubID != "" && !slices.Contains(clubIDs, clubID) {
+				clubIDs = append(clubIDs, clubID)
 			}
 
 			for _, rsvpStatus := range event.RSVPStatuses {
@@ -342,13 +538,6 @@ func (h *handler) raffleWinners(ctx context.Context, raffle database.Raffle, pas
 					continue
 				}
 
-				// Skip if the user is already in the members
-				if raffle.SingleEntry && slices.ContainsFunc(members, func(member campfire.Member) bool {
-					return member.ID == rsvpStatus.UserID
-				}) {
-					continue
-				}
-
 				// Skip if the user is a past winner & confirmed
 				if slices.ContainsFunc(pastWinners, func(pastWinner database.RaffleWinnerWithMember) bool {
 					return pastWinner.Member.ID == rsvpStatus.UserID && pastWinner.Confirmed
@@ -362,7 +551,37 @@ func (h *handler) raffleWinners(ctx context.Context, raffle database.Raffle, pas
 					continue
 				}
 
-				members = append(members, member)
+				// Skip if the member is excluded by the club
+				if exclusions.excludes(member) {
+					continue
+				}
+
+				var tickets int
+				switch raffle.Weighting {
+				case database.RaffleWeightingCheckIns:
+					if rsvpStatus.RSVPStatus == "CHECKED_IN" {
+						tickets = 1
+					}
+				default:
+					tickets = 1
+				}
+
+				i := slices.IndexFunc(entrants, func(entrant raffleEntrant) bool {
+					return entrant.Member.ID == member.ID
+				})
+				if i == -1 {
+					entrants = append(entrants, raffleEntrant{
+						Member:  member,
+						Tickets: tickets,
+					})
+					continue
+				}
+
+				// Single entry only limits the unweighted mode, check-in weighting always counts every check-in
+				if raffle.SingleEntry && raffle.Weighting != database.RaffleWeightingCheckIns {
+					continue
+				}
+				entrants[i].Tickets += tickets
 			}
 
 			return nil
@@ -372,27 +591,215 @@ func (h *handler) raffleWinners(ctx context.Context, raffle database.Raffle, pas
 		return nil, err
 	}
 
-	winners := make([]campfire.Member, 0, raffle.WinnerCount)
-	for {
-		if len(members) == 0 || len(winners) >= raffle.WinnerCount {
+	var quarterCheckIns map[string]int
+	if raffle.BonusMinCheckIns > 0 && raffle.BonusTickets > 0 {
+		var err error
+		quarterCheckIns, err = h.raffleQuarterCheckIns(ctx, raffle, clubIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range entrants {
+		// Accepted members without check-ins only get a ticket if the raffle gives everyone at least one
+		if raffle.MinOneTicket {
+			entrants[i].Tickets = max(entrants[i].Tickets, 1)
+		}
+
+		if quarterCheckIns != nil && quarterCheckIns[entrants[i].Member.ID] >= raffle.BonusMinCheckIns {
+			entrants[i].Tickets += raffle.BonusTickets
+		}
+
+		if raffle.MaxTickets > 0 {
+			entrants[i].Tickets = min(entrants[i].Tickets, raffle.MaxTickets)
+		}
+	}
+
+	// Entrants without tickets can not win
+	entrants = slices.DeleteFunc(entrants, func(entrant raffleEntrant) bool {
+		return entrant.Tickets == 0
+	})
+
+	return entrants, nil
+}
+
+// raffleExclusions are the raffle exclusion rules of a club.
+type raffleExclusions struct {
+	memberIDs            []string
+	communityAmbassadors bool
+}
+
+func (e raffleExclusions) excludes(member campfire.Member) bool {
+	if slices.Contains(e.memberIDs, member.ID) {
+		return true
+	}
+	return e.communityAmbassadors && campfire.MemberIsCommunityAmbassador(member.Badges)
+}
+
+// clubRaffleExclusions returns the blocked members of the club together with everyone who won a raffle of the club within the cooldown.
+func (h *handler) clubRaffleExclusions(ctx context.Context, clubID string) (raffleExclusions, error) {
+	club, err := h.DB.GetClub(ctx, clubID)
+	if err != nil {
+		return raffleExclusions{}, fmt.Errorf("failed to get club %q: %w", clubID, err)
+	}
+
+	memberIDs := slices.Clone(club.Club.RaffleBlockedMembers)
+	if club.Club.RaffleCooldownDays > 0 {
+		since := time.Now().AddDate(0, 0, -club.Club.RaffleCooldownDays)
+		winnerIDs, err := h.DB.GetClubRaffleWinnerIDs(ctx, clubID, since)
+		if err != nil {
+			return raffleExclusions{}, err
+		}
+		memberIDs = append(memberIDs, winnerIDs...)
+	}
+
+	return raffleExclusions{
+		memberIDs:            memberIDs,
+		communityAmbassadors: club.Club.RaffleExcludeCommunityAmbassadors,
+	}, nil
+}
+
+// raffleQuarterCheckIns returns the check-ins per member in the quarter the raffle was created in, across all clubs of the raffle events.
+func (h *handler) raffleQuarterCheckIns(ctx context.Context, raffle database.Raffle, clubIDs []string) (map[string]int, error) {
+	createdAt := raffle.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	from, to := xtime.GetQuarterRange(createdAt)
+
+	checkIns := make(map[string]int)
+	for _, clubID := range clubIDs {
+		members, err := h.DB.GetTopMembersByClub(ctx, clubID, from, to, false, "", -1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get quarter check-ins for club %q: %w", clubID, err)
+		}
+		for _, member := range members {
+			checkIns[member.ID] += member.CheckIns
+		}
+	}
+
+	return checkIns, nil
+}
+
+// drawRaffleWinners draws up to count winners from the entrant snapshot, where the chance of each entrant is proportional to their tickets.
+// Winners are removed from the pool, so nobody can win twice.
+// The draw is fully determined by the order of the entrants and the seed, which allows anybody to verify it later.
+func drawRaffleWinners(entrants []database.RaffleDrawEntrant, count int, seed string) []string {
+	entrants = slices.Clone(entrants)
+	rng := xrand.NewSeeded(seed)
+
+	winners := make([]string, 0, count)
+	for len(entrants) > 0 && len(winners) < count {
+		var totalTickets int
+		for _, entrant := range entrants {
+			totalTickets += entrant.Tickets
+		}
+		if totalTickets <= 0 {
 			break
 		}
-		num := rand.N(len(members))
-		member := members[num]
-		members = slices.Delete(members, num, num+1) // Remove selected member to avoid duplicates
 
-		// If the member has already won, skip them.
-		// This can happen if singleEntry is false.
-		if slices.ContainsFunc(winners, func(winner campfire.Member) bool {
-			return winner.ID == member.ID
-		}) {
+		ticket := rng.N(totalTickets)
+		for i, entrant := range entrants {
+			if ticket < entrant.Tickets {
+				winners = append(winners, entrant.MemberID)
+				entrants = slices.Delete(entrants, i, i+1)
+				break
+			}
+			ticket -= entrant.Tickets
+		}
+	}
+
+	return winners
+}
+
+// parseRafflePrizeTiers parses the prize tier rows of the raffle form, rows without a name are ignored.
+// Linked rewards must be accessible by the user.
+func (h *handler) parseRafflePrizeTiers(ctx context.Context, form url.Values, userID string) ([]database.RafflePrizeTier, error) {
+	names := form["tier_name"]
+	counts := form["tier_count"]
+	rewardIDs := form["tier_reward"]
+
+	var tiers []database.RafflePrizeTier
+	for i, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
 			continue
 		}
 
-		winners = append(winners, member)
+		count := 1
+		if i < len(counts) && counts[i] != "" {
+			var err error
+			count, err = strconv.Atoi(counts[i])
+			if err != nil || count < 1 {
+				return nil, fmt.Errorf("invalid number of winners for prize tier %q", name)
+			}
+		}
+
+		var rewardID *int
+		if i < len(rewardIDs) {
+			var err error
+			rewardID, err = h.parseRaffleReward(ctx, rewardIDs[i], userID)
+			if err != nil {
+				return nil, fmt.Errorf("prize tier %q: %w", name, err)
+			}
+		}
+
+		tiers = append(tiers, database.RafflePrizeTier{
+			Position: len(tiers),
+			Name:     name,
+			Count:    count,
+			RewardID: rewardID,
+		})
+	}
+
+	if len(tiers) > 10 {
+		return nil, fmt.Errorf("please limit the number of prize tiers to 10, got %d.", len(tiers))
+	}
+
+	return tiers, nil
+}
+
+// parseRaffleReward parses the ID of a reward linked to a raffle, which must be accessible by the user.
+// An empty value means no reward.
+func (h *handler) parseRaffleReward(ctx context.Context, value string, userID string) (*int, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	rewardID, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reward %q", value)
+	}
+	if userID == "" {
+		return nil, errors.New("you need to be logged in to link rewards")
+	}
+	if _, err = h.DB.GetReward(ctx, rewardID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("reward %d not found", rewardID)
+		}
+		return nil, fmt.Errorf("failed to get reward %d: %w", rewardID, err)
+	}
+
+	return &rewardID, nil
+}
+
+// rafflePrizeTierSlots returns the prize tier ID of every winner to draw, in draw order.
+// Confirmed winners keep their prize on rerun, so only the remaining places of a tier are drawn again.
+func rafflePrizeTierSlots(tiers []database.RafflePrizeTierWithReward, pastWinners []database.RaffleWinnerWithMember) []int {
+	var slots []int
+	for _, tier := range tiers {
+		var confirmed int
+		for _, pastWinner := range pastWinners {
+			if pastWinner.Confirmed && pastWinner.PrizeTierID != nil && *pastWinner.PrizeTierID == tier.ID {
+				confirmed++
+			}
+		}
+		for range max(tier.Count-confirmed, 0) {
+			slots = append(slots, tier.ID)
+		}
 	}
 
-	return winners, nil
+	return slots
 }
 
 func (h *handler) fetchRaffleRenderEvents(ctx context.Context, clubID string, eventIDs []string) ([]models.Event, error) {
@@ -443,7 +850,8 @@ func (h *handler) fetchRaffleRenderEvents(ctx context.Context, clubID string, ev
 	return results, nil
 }
 
-func (h *handler) processRaffleWinners(ctx context.Context, raffleID int, winners []campfire.Member, create bool) error {
+// processRaffleWinners stores the drawn winners. The n-th winner wins the prize tier in slots[n], if there is one.
+func (h *handler) processRaffleWinners(ctx context.Context, raffleID int, winners []campfire.Member, slots []int) error {
 	if len(winners) > 0 {
 		members := make([]database.Member, 0, len(winners))
 		for _, winner := range winners {
@@ -460,21 +868,27 @@ func (h *handler) processRaffleWinners(ctx context.Context, raffleID int, winner
 		}
 	}
 
-	if !create {
-		if err := h.DB.DeleteNotConfirmedRaffleWinners(ctx, raffleID); err != nil {
-			return err
-		}
-		if err := h.DB.MarkRaffleWinnersAsPast(ctx, raffleID); err != nil {
-			return err
-		}
+	if err := h.DB.DeleteNotConfirmedRaffleWinners(ctx, raffleID); err != nil {
+		return err
+	}
+	if err := h.DB.MarkRaffleWinnersAsPast(ctx, raffleID); err != nil {
+		return err
 	}
 
 	if len(winners) > 0 {
-		winnerIDs := make([]string, 0, len(winners))
-		for _, winner := range winners {
-			winnerIDs = append(winnerIDs, winner.ID)
+		dbWinners := make([]database.RaffleWinner, 0, len(winners))
+		for i, winner := range winners {
+			var prizeTierID *int
+			if i < len(slots) {
+				prizeTierID = &slots[i]
+			}
+			dbWinners = append(dbWinners, database.RaffleWinner{
+				RaffleID:    raffleID,
+				MemberID:    winner.ID,
+				PrizeTierID: prizeTierID,
+			})
 		}
-		if err := h.DB.InsertRaffleWinners(ctx, raffleID, winnerIDs); err != nil {
+		if err := h.DB.InsertRaffleWinners(ctx, dbWinners); err != nil {
 			return err
 		}
 	}
@@ -583,8 +997,15 @@ func (h *handler) renderRaffle(w http.ResponseWriter, r *http.Request, raffles [
 
 	eventID := query.Get("event")
 
-	if err := h.Templates().ExecuteTemplate(w, "raffle.gohtml", RaffleVars{
+	rewards, err := h.getRaffleRewards(r)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch rewards", slog.Any("err", err))
+		errorMessage = "Failed to fetch rewards: " + err.Error()
+	}
+
+	if err = h.Templates().ExecuteTemplate(w, "raffle.gohtml", RaffleVars{
 		Raffles:         raffles,
+		Rewards:         rewards,
 		SelectedEventID: eventID,
 		Error:           errorMessage,
 	}); err != nil {
@@ -592,6 +1013,25 @@ func (h *handler) renderRaffle(w http.ResponseWriter, r *http.Request, raffles [
 	}
 }
 
+// getRaffleRewards returns the rewards the user can link to prize tiers.
+func (h *handler) getRaffleRewards(r *http.Request) ([]models.Reward, error) {
+	session := auth.GetSession(r)
+	if session.UserID == "" {
+		return nil, nil
+	}
+
+	rewards, err := h.DB.GetRewards(r.Context(), session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	rewardModels := make([]models.Reward, 0, len(rewards))
+	for _, reward := range rewards {
+		rewardModels = append(rewardModels, models.NewReward(reward))
+	}
+	return rewardModels, nil
+}
+
 func (h *handler) renderRaffleResult(w http.ResponseWriter, r *http.Request, raffle database.Raffle, clubID string, errorMessage string) {
 	ctx := r.Context()
 
@@ -0,0 +1,182 @@
+package tracker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/topi314/campfire-tools/server/web/models"
+)
+
+type RaffleDisplayVars struct {
+	RaffleID  int
+	EventsURL string
+}
+
+type raffleDisplayState struct {
+	Draws    int                   `json:"draws"`
+	Entrants int                   `json:"entrants"`
+	Tickets  int                   `json:"tickets"`
+	Names    []string              `json:"names"`
+	Winners  []raffleDisplayWinner `json:"winners"`
+}
+
+type raffleDisplayWinner struct {
+	Name      string `json:"name"`
+	AvatarURL string `json:"avatar_url"`
+	PrizeTier string `json:"prize_tier"`
+	Confirmed bool   `json:"confirmed"`
+}
+
+// RaffleDisplay renders the read-only live view of a raffle meant for projecting at events.
+// It is accessible without a session by anybody with the signed display token.
+func (h *handler) RaffleDisplay(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	raffleID, ok := h.raffleDisplayID(r)
+	if !ok {
+		h.NotFound(w, r)
+		return
+	}
+
+	if _, err := h.DB.GetRaffleByID(ctx, raffleID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			h.NotFound(w, r)
+			return
+		}
+		slog.ErrorContext(ctx, "Failed to get raffle from database", slog.Any("err", err))
+		http.Error(w, "Failed to get raffle: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.Templates().ExecuteTemplate(w, "raffle_display.gohtml", RaffleDisplayVars{
+		RaffleID:  raffleID,
+		EventsURL: fmt.Sprintf("/raffle/%d/display/events?%s", raffleID, url.Values{"token": {r.URL.Query().Get("token")}}.Encode()),
+	}); err != nil {
+		slog.ErrorContext(ctx, "Failed to render raffle display template", slog.Any("err", err))
+	}
+}
+
+// RaffleDisplayEvents streams the state of a raffle over SSE whenever a winner is confirmed or the raffle is rerun.
+func (h *handler) RaffleDisplayEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	raffleID, ok := h.raffleDisplayID(r)
+	if !ok {
+		h.NotFound(w, r)
+		return
+	}
+
+	updates, unsubscribe := h.RaffleUpdates.Subscribe(raffleID)
+	defer unsubscribe()
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	send := func() error {
+		state, err := h.raffleDisplayState(ctx, raffleID)
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(state)
+		if err != nil {
+			return err
+		}
+		if _, err = fmt.Fprintf(w, "event: update\ndata: %s\n\n", data); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	if err := send(); err != nil {
+		slog.ErrorContext(ctx, "Failed to send raffle display state", slog.Int("raffle_id", raffleID), slog.Any("err", err))
+		return
+	}
+
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-updates:
+			if err := send(); err != nil {
+				slog.ErrorContext(ctx, "Failed to send raffle display state", slog.Int("raffle_id", raffleID), slog.Any("err", err))
+				return
+			}
+		case <-ticker.C:
+			// Keep proxies from closing the idle connection
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (h *handler) raffleDisplayID(r *http.Request) (int, bool) {
+	raffleID, err := strconv.Atoi(r.PathValue("raffle_id"))
+	if err != nil {
+		return 0, false
+	}
+
+	if !h.VerifyRaffleDisplayToken(raffleID, r.URL.Query().Get("token")) {
+		return 0, false
+	}
+
+	return raffleID, true
+}
+
+func (h *handler) raffleDisplayState(ctx context.Context, raffleID int) (*raffleDisplayState, error) {
+	draws, err := h.DB.GetRaffleDraws(ctx, raffleID)
+	if err != nil {
+		return nil, err
+	}
+
+	winners, err := h.DB.GetRaffleWinners(ctx, raffleID)
+	if err != nil {
+		return nil, err
+	}
+
+	state := raffleDisplayState{
+		Draws:   len(draws),
+		Names:   []string{},
+		Winners: []raffleDisplayWinner{},
+	}
+	if len(draws) > 0 {
+		entrants := draws[len(draws)-1].Entrants.V
+		state.Entrants = len(entrants)
+		for _, entrant := range entrants {
+			state.Tickets += entrant.Tickets
+			state.Names = append(state.Names, entrant.DisplayName)
+		}
+	}
+
+	for _, winner := range winners {
+		// Past winners only stay on the display once they are confirmed
+		if winner.Past && !winner.Confirmed {
+			continue
+		}
+		winnerModel := models.NewWinner(winner, "")
+		state.Winners = append(state.Winners, raffleDisplayWinner{
+			Name:      winnerModel.DisplayName,
+			AvatarURL: winnerModel.AvatarURL,
+			PrizeTier: winnerModel.PrizeTier,
+			Confirmed: winnerModel.Confirmed,
+		})
+	}
+
+	return &state, nil
+}
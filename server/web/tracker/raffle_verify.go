@@ -0,0 +1,61 @@
+package tracker
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/topi314/campfire-tools/server/web/models"
+)
+
+type RaffleVerifyVars struct {
+	RaffleID           int
+	NextSeedCommitment string
+	Draws              []models.RaffleDraw
+}
+
+// VerifyRaffle shows every recorded draw of a raffle and draws the winners again from the revealed seed and entrant snapshot.
+// The page is public so members can check a result without access to the raffle itself.
+func (h *handler) VerifyRaffle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	raffleID, err := strconv.Atoi(r.PathValue("raffle_id"))
+	if err != nil {
+		h.NotFound(w, r)
+		return
+	}
+
+	raffle, err := h.DB.GetRaffleByID(ctx, raffleID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			h.NotFound(w, r)
+			return
+		}
+		slog.ErrorContext(ctx, "Failed to get raffle from database", slog.Any("err", err))
+		http.Error(w, "Failed to get raffle: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	draws, err := h.DB.GetRaffleDraws(ctx, raffleID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get raffle draws from database", slog.Any("err", err))
+		http.Error(w, "Failed to get raffle draws: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	verifiedDraws := make([]models.RaffleDraw, 0, len(draws))
+	for i, draw := range draws {
+		computedWinners := drawRaffleWinners(draw.Entrants.V, draw.WinnerCount, draw.Seed)
+		verifiedDraws = append(verifiedDraws, models.NewRaffleDraw(i+1, draw, computedWinners))
+	}
+
+	if err = h.Templates().ExecuteTemplate(w, "raffle_verify.gohtml", RaffleVerifyVars{
+		RaffleID:           raffle.ID,
+		NextSeedCommitment: raffle.SeedCommitment,
+		Draws:              verifiedDraws,
+	}); err != nil {
+		slog.ErrorContext(ctx, "Failed to render raffle verify template", slog.Any("err", err))
+	}
+}
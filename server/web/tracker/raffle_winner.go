@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"slices"
 	"strconv"
 
 	"github.com/topi314/campfire-tools/server/auth"
 	"github.com/topi314/campfire-tools/server/database"
+	"github.com/topi314/campfire-tools/server/web/models"
 )
 
 func (h *handler) ConfirmRaffleWinner(w http.ResponseWriter, r *http.Request) {
@@ -53,7 +55,13 @@ func (h *handler) ConfirmRaffleWinner(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err = h.DB.ConfirmRaffleWinner(ctx, raffleID, memberID); err != nil {
+	var redeemedBy *string
+	if session.UserID != "" {
+		redeemedBy = &session.UserID
+	}
+
+	rewardCodeID, confirmed, err := h.DB.ConfirmRaffleWinner(ctx, raffleID, memberID, redeemedBy)
+	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			slog.ErrorContext(ctx, "Raffle winner not found", slog.String("member_id", memberID), slog.Int("raffle_id", raffleID))
 			h.renderRaffleResult(w, r, database.Raffle{}, clubID, "Raffle winner not found")
@@ -64,6 +72,20 @@ func (h *handler) ConfirmRaffleWinner(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.RaffleUpdates.Publish(raffleID)
+
+	if confirmed {
+		if err = h.PublishRaffleWinnerConfirmed(ctx, *raffle, memberID, rewardCodeID); err != nil {
+			slog.ErrorContext(ctx, "Failed to publish raffle winner confirmed webhook", slog.Int("raffle_id", raffleID), slog.String("member_id", memberID), slog.Any("err", err))
+		}
+	}
+
+	// Hand the code to the winner right away
+	if rewardCodeID != nil {
+		http.Redirect(w, r, raffleWinnerURL(raffleID, clubID, memberID), http.StatusSeeOther)
+		return
+	}
+
 	var rawQuery string
 	if pastWinnersOpen {
 		rawQuery = "past-winners=true"
@@ -72,6 +94,85 @@ func (h *handler) ConfirmRaffleWinner(w http.ResponseWriter, r *http.Request) {
 	redirectRaffle(w, r, raffleID, clubID, rawQuery)
 }
 
+type RaffleWinnerVars struct {
+	models.Winner
+	RewardCodeURL string
+	BackURL       string
+}
+
+func (h *handler) GetRaffleWinner(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	clubID := r.PathValue("club_id")
+	memberID := r.PathValue("member_id")
+
+	raffleID, err := strconv.Atoi(r.PathValue("raffle_id"))
+	if err != nil {
+		h.NotFound(w, r)
+		return
+	}
+
+	raffle, err := h.DB.GetRaffleByID(ctx, raffleID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			h.NotFound(w, r)
+			return
+		}
+		slog.ErrorContext(ctx, "Failed to get raffle from database", slog.Any("err", err))
+		http.Error(w, "Failed to get raffle: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	session := auth.GetSession(r)
+	if raffle.UserID != "" && raffle.UserID != session.UserID {
+		h.NotFound(w, r)
+		return
+	}
+
+	winners, err := h.DB.GetRaffleWinners(ctx, raffleID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get raffle winners from database", slog.Any("err", err))
+		http.Error(w, "Failed to get raffle winners: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	i := slices.IndexFunc(winners, func(winner database.RaffleWinnerWithMember) bool {
+		return winner.Member.ID == memberID
+	})
+	if i == -1 {
+		h.NotFound(w, r)
+		return
+	}
+	winner := winners[i]
+
+	var rewardCodeURL string
+	if winner.RewardCodeRedeemCode != "" {
+		rewardCodeURL = models.RewardCodeURL(h.Cfg.Server.PublicRewardsURL, winner.RewardCodeRedeemCode)
+	}
+
+	var backURL string
+	if clubID != "" {
+		backURL = fmt.Sprintf("/tracker/club/%s/raffle/%d", clubID, raffleID)
+	} else {
+		backURL = fmt.Sprintf("/raffle/%d", raffleID)
+	}
+
+	if err = h.Templates().ExecuteTemplate(w, "raffle_winner.gohtml", RaffleWinnerVars{
+		Winner:        models.NewWinner(winner, clubID),
+		RewardCodeURL: rewardCodeURL,
+		BackURL:       backURL,
+	}); err != nil {
+		slog.ErrorContext(ctx, "Failed to render raffle winner template", slog.Any("err", err))
+	}
+}
+
+func raffleWinnerURL(raffleID int, clubID string, memberID string) string {
+	if clubID != "" {
+		return fmt.Sprintf("/tracker/club/%s/raffle/%d/winner/%s", clubID, raffleID, memberID)
+	}
+	return fmt.Sprintf("/raffle/%d/winner/%s", raffleID, memberID)
+}
+
 func redirectRaffle(w http.ResponseWriter, r *http.Request, raffleID int, clubID string, rawQuery string) {
 	var redirectURL string
 	if clubID != "" {
@@ -190,6 +190,10 @@ func (h *handler) TrackerRewardCodeMarkAsUsed(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	if err = h.PublishRewardCodeRedeemed(ctx, codeID); err != nil {
+		slog.ErrorContext(ctx, "Failed to publish reward code redeemed webhook", slog.Int("reward_code_id", codeID), slog.Any("err", err))
+	}
+
 	http.Redirect(w, r, fmt.Sprintf("/tracker/rewards/%d", id), http.StatusSeeOther)
 }
 
@@ -16,6 +16,7 @@ func Routes(srv *server.Server) http.Handler {
 	h := &handler{
 		Server: srv,
 	}
+	srv.PresetExporter = h.exportPreset
 
 	fs := srv.Reloader.CacheMiddleware(http.FileServer(h.StaticFS))
 
@@ -26,6 +27,19 @@ func Routes(srv *server.Server) http.Handler {
 
 	mux.HandleFunc("GET /admin", h.Admin)
 	mux.HandleFunc("POST /admin/tokens", h.AdminTokens)
+	mux.HandleFunc("POST /admin/jobs/{job_id}/retry", h.AdminRetryJob)
+	mux.HandleFunc("POST /admin/jobs/{job_id}/cancel", h.AdminCancelJob)
+
+	mux.HandleFunc("GET    /admin/events", h.AdminEvents)
+	mux.HandleFunc("POST   /admin/event-categories", h.PostAdminEventCategory)
+	mux.HandleFunc("POST   /admin/event-categories/{event_category_id}", h.PostAdminEventCategory)
+	mux.HandleFunc("DELETE /admin/event-categories/{event_category_id}", h.AdminEventCategoryDelete)
+	mux.HandleFunc("POST   /admin/event-category-patterns", h.PostAdminEventCategoryPattern)
+	mux.HandleFunc("POST   /admin/digital-code-exclude-patterns", h.PostAdminDigitalCodeExcludePattern)
+	mux.HandleFunc("DELETE /admin/digital-code-exclude-patterns/{pattern_id}", h.AdminDigitalCodeExcludePatternDelete)
+	mux.HandleFunc("POST   /admin/configured-events", h.PostAdminConfiguredEvent)
+	mux.HandleFunc("POST   /admin/configured-events/{configured_event_id}", h.PostAdminConfiguredEvent)
+	mux.HandleFunc("DELETE /admin/configured-events/{configured_event_id}", h.AdminConfiguredEventDelete)
 
 	mux.HandleFunc("GET  /event", h.Event)
 	mux.HandleFunc("POST /event", h.ShowEvent)
@@ -35,9 +49,13 @@ func Routes(srv *server.Server) http.Handler {
 	mux.HandleFunc("POST /raffle", h.RunRaffle)
 	mux.HandleFunc("POST /raffle/{raffle_id}", h.RerunRaffle)
 	mux.HandleFunc("GET  /raffle/{raffle_id}", h.GetRaffle)
+	mux.HandleFunc("GET  /raffle/{raffle_id}/verify", h.VerifyRaffle)
+	mux.HandleFunc("GET  /raffle/{raffle_id}/display", h.RaffleDisplay)
+	mux.HandleFunc("GET  /raffle/{raffle_id}/display/events", h.RaffleDisplayEvents)
 	mux.HandleFunc("GET  /raffle/{raffle_id}/events", h.AddRaffleEvents)
 	mux.HandleFunc("POST /raffle/{raffle_id}/events", h.PostAddRaffleEvents)
 	mux.HandleFunc("POST /raffle/{raffle_id}/confirm/{member_id}", h.ConfirmRaffleWinner)
+	mux.HandleFunc("GET  /raffle/{raffle_id}/winner/{member_id}", h.GetRaffleWinner)
 
 	mux.HandleFunc("GET  /export", h.Export)
 	mux.HandleFunc("POST /export", h.DoExport)
@@ -57,6 +75,10 @@ func Routes(srv *server.Server) http.Handler {
 	mux.HandleFunc("POST /tracker/rewards/{id}/codes/{code_id}/mark-unused", h.TrackerRewardCodeMarkAsUnused)
 	mux.Handle("GET /tracker/rewards/{id}/codes/{code_id}/qr", middlewares.Cache(http.HandlerFunc(h.TrackerRewardCodeQR)))
 
+	mux.HandleFunc("GET    /tracker/settings", h.TrackerSettings)
+	mux.HandleFunc("POST   /tracker/settings/api-keys", h.PostTrackerSettingsAPIKey)
+	mux.HandleFunc("DELETE /tracker/settings/api-keys/{api_key_id}", h.TrackerSettingsAPIKeyDelete)
+
 	mux.HandleFunc("GET  /tracker/code/{code}", h.TrackerCode)
 	mux.HandleFunc("POST /tracker/code/{code}", h.PostTrackerCode)
 
@@ -64,6 +86,7 @@ func Routes(srv *server.Server) http.Handler {
 	mux.HandleFunc("GET /tracker/login/callback", h.LoginCallback)
 
 	mux.HandleFunc("GET /tracker/clubs", h.TrackerClubs)
+	mux.HandleFunc("GET /tracker/leaderboard", h.TrackerLeaderboard)
 	mux.HandleFunc("GET /tracker/event-stats", h.TrackerEventStats)
 	mux.HandleFunc("GET /tracker/members", h.TrackerMembers)
 	mux.HandleFunc("GET /tracker/members/{member_id}", h.TrackerMember)
@@ -78,11 +101,16 @@ func Routes(srv *server.Server) http.Handler {
 	mux.HandleFunc("GET  /tracker/club/{club_id}/events", h.TrackerClubEvents)
 	mux.HandleFunc("GET  /tracker/club/{club_id}/members", h.TrackerClubMembers)
 	mux.HandleFunc("GET  /tracker/club/{club_id}/member/{member_id}", h.TrackerClubMember)
+	mux.HandleFunc("GET  /tracker/club/{club_id}/hosts", h.TrackerClubHosts)
+	mux.HandleFunc("GET  /tracker/club/{club_id}/hosts/{member_id}", h.TrackerClubHost)
 
 	mux.HandleFunc("GET /tracker/quarter-filters", h.GetQuarterFilters)
 
-	mux.HandleFunc("GET  /tracker/club/{club_id}/export", h.TrackerClubExport)
-	mux.HandleFunc("POST /tracker/club/{club_id}/export", h.DoExport)
+	mux.HandleFunc("GET    /tracker/club/{club_id}/export", h.TrackerClubExport)
+	mux.HandleFunc("POST   /tracker/club/{club_id}/export", h.DoExport)
+	mux.HandleFunc("POST   /tracker/club/{club_id}/export/database", h.DoTrackerClubDatabaseExport)
+	mux.HandleFunc("POST   /tracker/club/{club_id}/export/presets", h.PostTrackerClubExportPreset)
+	mux.HandleFunc("DELETE /tracker/club/{club_id}/export/presets/{preset_id}", h.TrackerClubExportPresetDelete)
 
 	mux.HandleFunc("GET  /tracker/club/{club_id}/raffle", h.TrackerClubRaffle)
 	mux.HandleFunc("POST /tracker/club/{club_id}/raffle", h.RunRaffle)
@@ -91,17 +119,30 @@ func Routes(srv *server.Server) http.Handler {
 	mux.HandleFunc("GET  /tracker/club/{club_id}/raffle/{raffle_id}/events", h.AddRaffleEvents)
 	mux.HandleFunc("POST /tracker/club/{club_id}/raffle/{raffle_id}/events", h.PostAddRaffleEvents)
 	mux.HandleFunc("POST /tracker/club/{club_id}/raffle/{raffle_id}/confirm/{member_id}", h.ConfirmRaffleWinner)
+	mux.HandleFunc("GET  /tracker/club/{club_id}/raffle/{raffle_id}/winner/{member_id}", h.GetRaffleWinner)
+
+	mux.HandleFunc("POST   /tracker/club/{club_id}/calendar", h.TrackerClubCalendarToken)
+	mux.HandleFunc("DELETE /tracker/club/{club_id}/calendar", h.TrackerClubCalendarTokenDelete)
+
+	mux.HandleFunc("GET    /tracker/club/{club_id}/scheduled-exports", h.TrackerClubScheduledExports)
+	mux.HandleFunc("POST   /tracker/club/{club_id}/scheduled-exports", h.PostTrackerClubScheduledExport)
+	mux.HandleFunc("POST   /tracker/club/{club_id}/scheduled-exports/{scheduled_export_id}/run", h.TrackerClubScheduledExportRun)
+	mux.HandleFunc("DELETE /tracker/club/{club_id}/scheduled-exports/{scheduled_export_id}", h.TrackerClubScheduledExportDelete)
+
+	mux.HandleFunc("GET    /tracker/club/{club_id}/webhooks", h.TrackerClubWebhooks)
+	mux.HandleFunc("POST   /tracker/club/{club_id}/webhooks", h.PostTrackerClubWebhook)
+	mux.HandleFunc("DELETE /tracker/club/{club_id}/webhooks/{webhook_id}", h.TrackerClubWebhookDelete)
 
 	mux.HandleFunc("GET  /tracker/event/import", h.TrackerEventImport)
 	mux.HandleFunc("POST /tracker/event/import", h.TrackerEventDoImport)
 
 	mux.HandleFunc("GET /tracker/event/{event_id}", h.TrackerClubEvent)
 	mux.HandleFunc("GET /tracker/event/{event_id}/refresh", h.TrackerClubEventRefresh)
+	mux.HandleFunc("GET /tracker/event/{event_id}/timeline", h.TrackerClubEventTimeline)
 
 	mux.HandleFunc("GET  /api/docs", h.APIDocs)
-	mux.HandleFunc("GET  /api/events", h.APIExportEvents)
-	mux.HandleFunc("POST /api/events", h.APIImportEvents)
-	mux.HandleFunc("GET  /api/clubs/{club_id}/events", h.APIClubEvents)
+	mux.HandleFunc("GET  /api/openapi.json", h.APIOpenAPI)
+	h.registerAPIRoutes(mux)
 
 	mux.HandleFunc("GET /images/{image_id}", h.Image)
 
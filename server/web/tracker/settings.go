@@ -0,0 +1,153 @@
+package tracker
+
+import (
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/topi314/campfire-tools/internal/xquery"
+	"github.com/topi314/campfire-tools/server"
+	"github.com/topi314/campfire-tools/server/auth"
+	"github.com/topi314/campfire-tools/server/database"
+	"github.com/topi314/campfire-tools/server/web/models"
+)
+
+const (
+	apiKeyDefaultRateLimit = 60
+	apiKeyMaxRateLimit     = 600
+)
+
+type TrackerSettingsVars struct {
+	APIKeys          []models.APIKey
+	Scopes           []database.APIKeyScope
+	Clubs            []database.ClubRef
+	DefaultRateLimit int
+	MaxRateLimit     int
+	NewAPIKey        string
+	Error            string
+}
+
+func (h *handler) TrackerSettings(w http.ResponseWriter, r *http.Request) {
+	h.renderTrackerSettings(w, r, "", "")
+}
+
+func (h *handler) renderTrackerSettings(w http.ResponseWriter, r *http.Request, newAPIKey string, errorMessage string) {
+	ctx := r.Context()
+	session := auth.GetSession(r)
+
+	keys, err := h.DB.GetAPIKeys(ctx, session.UserID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get API keys", slog.Any("err", err))
+		http.Error(w, "Failed to get API keys: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	clubs, err := h.DB.GetClubOptions(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get clubs", slog.Any("err", err))
+		http.Error(w, "Failed to get clubs: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	clubNames := make(map[string]string, len(clubs))
+	for _, club := range clubs {
+		clubNames[club.ID] = club.Name
+	}
+
+	apiKeys := make([]models.APIKey, len(keys))
+	for i, key := range keys {
+		apiKeys[i] = models.NewAPIKey(key, clubNames)
+	}
+
+	if err = h.Templates().ExecuteTemplate(w, "tracker_settings.gohtml", TrackerSettingsVars{
+		APIKeys:          apiKeys,
+		Scopes:           database.AllAPIKeyScopes,
+		Clubs:            clubs,
+		DefaultRateLimit: apiKeyDefaultRateLimit,
+		MaxRateLimit:     apiKeyMaxRateLimit,
+		NewAPIKey:        newAPIKey,
+		Error:            errorMessage,
+	}); err != nil {
+		slog.ErrorContext(ctx, "Failed to render tracker settings template", slog.Any("err", err))
+	}
+}
+
+func (h *handler) PostTrackerSettingsAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	session := auth.GetSession(r)
+
+	if err := r.ParseForm(); err != nil {
+		h.renderTrackerSettings(w, r, "", "Failed to parse form data: "+err.Error())
+		return
+	}
+
+	name := strings.TrimSpace(r.Form.Get("name"))
+	rateLimit := xquery.ParseInt(r.Form, "rate_limit", apiKeyDefaultRateLimit)
+
+	clubIDs := []string{}
+	for _, clubID := range r.Form["club_ids"] {
+		if clubID != "" && !slices.Contains(clubIDs, clubID) {
+			clubIDs = append(clubIDs, clubID)
+		}
+	}
+
+	var scopes []string
+	for _, scope := range r.Form["scopes"] {
+		if !slices.Contains(database.AllAPIKeyScopes, database.APIKeyScope(scope)) {
+			h.renderTrackerSettings(w, r, "", "Invalid scope: "+scope)
+			return
+		}
+		scopes = append(scopes, scope)
+	}
+
+	if name == "" {
+		h.renderTrackerSettings(w, r, "", "Name cannot be empty")
+		return
+	}
+	if len(scopes) == 0 {
+		h.renderTrackerSettings(w, r, "", "Select at least one scope")
+		return
+	}
+	if rateLimit < 1 || rateLimit > apiKeyMaxRateLimit {
+		h.renderTrackerSettings(w, r, "", "Rate limit must be between 1 and "+strconv.Itoa(apiKeyMaxRateLimit)+" requests per minute")
+		return
+	}
+
+	key := server.NewAPIKey()
+	if _, err := h.DB.InsertAPIKey(ctx, database.APIKey{
+		UserID:    session.UserID,
+		Name:      name,
+		Prefix:    key[:len(server.APIKeyPrefix)+8],
+		Hash:      server.HashAPIKey(key),
+		Scopes:    scopes,
+		ClubIDs:   clubIDs,
+		RateLimit: rateLimit,
+	}); err != nil {
+		slog.ErrorContext(ctx, "Failed to insert API key", slog.Any("err", err))
+		h.renderTrackerSettings(w, r, "", "Failed to create API key")
+		return
+	}
+
+	h.renderTrackerSettings(w, r, key, "")
+}
+
+func (h *handler) TrackerSettingsAPIKeyDelete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	session := auth.GetSession(r)
+
+	id, err := strconv.Atoi(r.PathValue("api_key_id"))
+	if err != nil {
+		h.NotFound(w, r)
+		return
+	}
+
+	if err = h.DB.DeleteAPIKey(ctx, id, session.UserID); err != nil {
+		slog.ErrorContext(ctx, "Failed to delete API key", slog.Any("err", err))
+		http.Error(w, "Failed to delete API key", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/tracker/settings", http.StatusSeeOther)
+}
@@ -0,0 +1,353 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/topi314/campfire-tools/internal/xrand"
+	"github.com/topi314/campfire-tools/server/campfire"
+	"github.com/topi314/campfire-tools/server/database"
+)
+
+const (
+	// WebhookSecretPrefix marks webhook signing secrets so they are easy to recognize.
+	WebhookSecretPrefix = "whsec_"
+
+	WebhookHeaderTopic     = "X-Campfire-Tools-Topic"
+	WebhookHeaderDelivery  = "X-Campfire-Tools-Delivery"
+	WebhookHeaderTimestamp = "X-Campfire-Tools-Timestamp"
+	WebhookHeaderSignature = "X-Campfire-Tools-Signature"
+
+	webhookTimeout           = 10 * time.Second
+	webhookDeliveryRetention = 30 * 24 * time.Hour
+)
+
+// ErrWebhookAddressNotAllowed is returned for webhooks which point to a loopback, private or link-local address.
+var ErrWebhookAddressNotAllowed = errors.New("webhook URL must not point to a loopback, private or link-local address")
+
+// sharedAddressSpace is the carrier-grade NAT range, which is not covered by netip.Addr.IsPrivate.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// ValidateWebhookURL checks that the URL uses https and that its host only resolves to public addresses.
+func ValidateWebhookURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+		return errors.New("URL must be a valid https URL")
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil {
+		return fmt.Errorf("failed to resolve webhook host: %w", err)
+	}
+	for _, addr := range addrs {
+		if !webhookAddrAllowed(addr) {
+			return ErrWebhookAddressNotAllowed
+		}
+	}
+
+	return nil
+}
+
+func webhookAddrAllowed(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !sharedAddressSpace.Contains(addr)
+}
+
+// newWebhookHttpClient returns the client webhooks are delivered with. It checks every address it connects to,
+// so a host which resolves to an internal address after the webhook was created can not be reached either.
+// Proxies from the environment and redirects are not followed.
+func newWebhookHttpClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: webhookTimeout,
+		Control: func(network string, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("failed to parse webhook address: %w", err)
+			}
+			if !webhookAddrAllowed(addrPort.Addr()) {
+				return ErrWebhookAddressNotAllowed
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: webhookTimeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// NewWebhookSecret returns a new random secret to sign webhook payloads with.
+func NewWebhookSecret() string {
+	return WebhookSecretPrefix + xrand.NewSeed()
+}
+
+// SignWebhook returns the signature of a webhook payload, the hex encoded HMAC-SHA256 of "<unix timestamp>.<body>" keyed with the webhook secret.
+func SignWebhook(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookPayload is the body sent to webhooks, Data depends on the topic.
+type WebhookPayload struct {
+	Topic     database.WebhookTopic `json:"topic"`
+	ClubID    string                `json:"club_id"`
+	CreatedAt time.Time             `json:"created_at"`
+	Data      any                   `json:"data"`
+}
+
+type WebhookEvent struct {
+	ID                           string    `json:"id"`
+	Name                         string    `json:"name"`
+	URL                          string    `json:"url"`
+	ClubID                       string    `json:"club_id"`
+	Time                         time.Time `json:"time"`
+	EndTime                      time.Time `json:"end_time"`
+	CreatedByCommunityAmbassador bool      `json:"created_by_community_ambassador"`
+	Accepted                     int       `json:"accepted"`
+	CheckedIn                    int       `json:"checked_in"`
+}
+
+type WebhookMember struct {
+	ID          string `json:"id"`
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name"`
+}
+
+type WebhookRaffleWinner struct {
+	RaffleID     int           `json:"raffle_id"`
+	Member       WebhookMember `json:"member"`
+	PrizeTier    string        `json:"prize_tier"`
+	RewardCodeID *int          `json:"reward_code_id"`
+}
+
+type WebhookRewardCode struct {
+	ID         int           `json:"id"`
+	RewardID   int           `json:"reward_id"`
+	RewardName string        `json:"reward_name"`
+	RedeemedAt *time.Time    `json:"redeemed_at"`
+	RaffleID   int           `json:"raffle_id"`
+	Member     WebhookMember `json:"member"`
+}
+
+// newWebhookEvent returns the webhook data of an event fetched from Campfire with the stored club ID of the event,
+// as the club ID of the Campfire payload can be empty.
+func newWebhookEvent(event campfire.Event, clubID string) WebhookEvent {
+	statuses := make([]string, len(event.RSVPStatuses))
+	for i, rsvp := range event.RSVPStatuses {
+		statuses[i] = rsvp.RSVPStatus
+	}
+	accepted, checkedIn := countRSVPs(statuses)
+
+	return WebhookEvent{
+		ID:                           event.ID,
+		Name:                         event.Name,
+		URL:                          campfire.EventURL(event.ID),
+		ClubID:                       clubID,
+		Time:                         event.EventTime,
+		EndTime:                      event.EventEndTime,
+		CreatedByCommunityAmbassador: event.CreatedByCommunityAmbassador,
+		Accepted:                     accepted,
+		CheckedIn:                    checkedIn,
+	}
+}
+
+func newWebhookMember(member database.Member) WebhookMember {
+	return WebhookMember{
+		ID:          member.ID,
+		Username:    member.Username,
+		DisplayName: member.DisplayName,
+	}
+}
+
+// countRSVPs returns the number of accepted (including checked in) and checked in RSVPs.
+func countRSVPs(statuses []string) (int, int) {
+	var accepted, checkedIn int
+	for _, status := range statuses {
+		switch status {
+		case "CHECKED_IN":
+			checkedIn++
+			accepted++
+		case "ACCEPTED":
+			accepted++
+		}
+	}
+	return accepted, checkedIn
+}
+
+// PublishWebhook queues a delivery of the data to every webhook of the club subscribed to the topic.
+func (s *Server) PublishWebhook(ctx context.Context, clubID string, topic database.WebhookTopic, data any) error {
+	payload, err := json.Marshal(WebhookPayload{
+		Topic:     topic,
+		ClubID:    clubID,
+		CreatedAt: time.Now(),
+		Data:      data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	deliveryIDs, err := s.DB.InsertWebhookDeliveries(ctx, clubID, topic, payload)
+	if err != nil {
+		return err
+	}
+
+	jobs := make([]database.Job, len(deliveryIDs))
+	for i, deliveryID := range deliveryIDs {
+		jobs[i] = s.NewJob(database.JobKindDeliverWebhook, strconv.Itoa(deliveryID))
+	}
+
+	return s.DB.InsertJobs(ctx, jobs)
+}
+
+// PublishRaffleWinnerConfirmed publishes the confirmed winner of a club raffle and the reward code they were handed, if any.
+func (s *Server) PublishRaffleWinnerConfirmed(ctx context.Context, raffle database.Raffle, memberID string, rewardCodeID *int) error {
+	if raffle.ClubID == nil {
+		return nil
+	}
+
+	winners, err := s.DB.GetRaffleWinners(ctx, raffle.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, winner := range winners {
+		if winner.RaffleWinner.MemberID != memberID {
+			continue
+		}
+		if err = s.PublishWebhook(ctx, *raffle.ClubID, database.WebhookTopicRaffleWinnerConfirmed, WebhookRaffleWinner{
+			RaffleID:     raffle.ID,
+			Member:       newWebhookMember(winner.Member),
+			PrizeTier:    winner.PrizeTierName,
+			RewardCodeID: rewardCodeID,
+		}); err != nil {
+			return err
+		}
+		break
+	}
+
+	if rewardCodeID == nil {
+		return nil
+	}
+	return s.PublishRewardCodeRedeemed(ctx, *rewardCodeID)
+}
+
+// PublishRewardCodeRedeemed publishes a redeemed reward code to the club of the raffle it was handed out in.
+// Codes which were not handed out in a club raffle do not belong to a club and are skipped.
+func (s *Server) PublishRewardCodeRedeemed(ctx context.Context, rewardCodeID int) error {
+	winner, err := s.DB.GetRewardCodeRaffleWinner(ctx, rewardCodeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	if winner.ClubID == nil {
+		return nil
+	}
+
+	return s.PublishWebhook(ctx, *winner.ClubID, database.WebhookTopicRewardCodeRedeemed, WebhookRewardCode{
+		ID:         winner.RewardCodeID,
+		RewardID:   winner.RewardID,
+		RewardName: winner.RewardName,
+		RedeemedAt: winner.RedeemedAt,
+		RaffleID:   winner.RaffleID,
+		Member:     newWebhookMember(winner.Member),
+	})
+}
+
+func (s *Server) runDeliverWebhookJob(ctx context.Context, job database.Job) error {
+	deliveryID, err := strconv.Atoi(job.Key)
+	if err != nil {
+		return fmt.Errorf("invalid webhook delivery ID: %w", err)
+	}
+
+	delivery, err := s.DB.GetWebhookDelivery(ctx, deliveryID)
+	if err != nil {
+		// the webhook was deleted together with its deliveries
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	responseStatus, deliverErr := s.deliverWebhook(ctx, delivery.Webhook, delivery.WebhookDelivery)
+
+	delivery.Attempts = job.Attempts
+	delivery.ResponseStatus = responseStatus
+	switch {
+	case deliverErr == nil:
+		delivery.Status = database.WebhookDeliveryStatusSucceeded
+		delivery.Error = ""
+	case job.Attempts >= job.MaxAttempts:
+		delivery.Status = database.WebhookDeliveryStatusFailed
+		delivery.Error = deliverErr.Error()
+	default:
+		delivery.Status = database.WebhookDeliveryStatusPending
+		delivery.Error = deliverErr.Error()
+	}
+
+	if err = s.DB.UpdateWebhookDelivery(context.WithoutCancel(ctx), delivery.WebhookDelivery); err != nil {
+		slog.ErrorContext(ctx, "Failed to update webhook delivery", slog.Int("delivery_id", deliveryID), slog.Any("err", err))
+	}
+
+	return deliverErr
+}
+
+// deliverWebhook sends the signed delivery payload to the webhook and returns the response status, if the webhook responded.
+func (s *Server) deliverWebhook(ctx context.Context, webhook database.Webhook, delivery database.WebhookDelivery) (*int, error) {
+	// webhooks created before only https was allowed are not delivered anymore
+	if u, err := url.Parse(webhook.URL); err != nil || u.Scheme != "https" {
+		return nil, errors.New("webhook URL must be a valid https URL")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	rq, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+
+	now := time.Now()
+	rq.Header.Set("Content-Type", "application/json")
+	rq.Header.Set(WebhookHeaderTopic, string(delivery.Topic))
+	rq.Header.Set(WebhookHeaderDelivery, strconv.Itoa(delivery.ID))
+	rq.Header.Set(WebhookHeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	rq.Header.Set(WebhookHeaderSignature, SignWebhook(webhook.Secret, now, delivery.Payload))
+
+	rs, err := s.WebhookHttpClient.Do(rq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer rs.Body.Close()
+
+	// the response body is never stored, it could leak the content of the target to the club members
+	if rs.StatusCode < 200 || rs.StatusCode >= 300 {
+		return &rs.StatusCode, fmt.Errorf("webhook responded with status %d", rs.StatusCode)
+	}
+
+	return &rs.StatusCode, nil
+}